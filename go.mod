@@ -4,6 +4,7 @@ go 1.24.0
 
 require (
 	github.com/bxcodec/faker/v3 v3.8.1
+	github.com/klauspost/compress v1.18.0
 	github.com/stretchr/testify v1.10.0
 )
 
@@ -1,6 +1,8 @@
 package isly
 
 import (
+	"context"
+	"io"
 	"os"
 	"reflect"
 )
@@ -8,14 +10,29 @@ import (
 type IISLYComponent interface {
 	// read
 	ReadFile(csvFile string) error
+	ReadFiles(csvFiles ...string) error
+	ReadGlob(pattern string) error
 	UnmarshalCSV(results interface{}) error
+	UnmarshalCSVContext(ctx context.Context, results interface{}) error
+	Close() error
 	processStructFromRecord(structValue reflect.Value, record []string, headerMap map[string]int) error
 }
 
 type newIslyComponent struct {
 	*os.File
+	decoder *Decoder
+
+	// CSV text of File, decompressed when needed
+	source       io.Reader
+	sourceCloser io.Closer
+	sourceRead   *islyByteCounter
+
+	// files given to ReadFiles or ReadGlob, opened one at a time while decoding
+	files []string
 }
 
-func NewIsly() IISLYComponent {
-	return &newIslyComponent{}
+// NewIsly returns a component with its own Decoder. Use NewDecoder and Decoder.NewIsly
+// to share one configuration between many components.
+func NewIsly(opts ...Option) IISLYComponent {
+	return NewDecoder(opts...).NewIsly()
 }
@@ -0,0 +1,88 @@
+package isly
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	// Index of each character in base58Alphabet, -1 for invalid characters
+	base58Index = func() [256]int {
+		var index [256]int
+		for i := range index {
+			index[i] = -1
+		}
+		for i := 0; i < len(base58Alphabet); i++ {
+			index[base58Alphabet[i]] = i
+		}
+		return index
+	}()
+
+	base58Radix = big.NewInt(58)
+)
+
+// islyParseBaseEncoding decodes value with one of the base64, base64url, rawbase64,
+// base32 or base58 tag types. Whitespace is ignored and, except for rawbase64,
+// padding is optional.
+func islyParseBaseEncoding(value string, encoding string) ([]byte, error) {
+	value = strings.Join(strings.Fields(value), "")
+
+	var result []byte
+	var err error
+
+	switch encoding {
+	case "base64":
+		result, err = islyDecodeWithOptionalPadding(value, base64.StdEncoding.DecodeString, base64.RawStdEncoding.DecodeString)
+	case "base64url":
+		result, err = islyDecodeWithOptionalPadding(value, base64.URLEncoding.DecodeString, base64.RawURLEncoding.DecodeString)
+	case "rawbase64":
+		result, err = base64.RawStdEncoding.DecodeString(value)
+	case "base32":
+		value = strings.ToUpper(value)
+		result, err = islyDecodeWithOptionalPadding(value, base32.StdEncoding.DecodeString, base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString)
+	case "base58":
+		result, err = islyDecodeBase58(value)
+	default:
+		return nil, fmt.Errorf("unknown encoding '%s'", encoding)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s value '%s': %w", encoding, value, err)
+	}
+
+	return result, nil
+}
+
+func islyDecodeWithOptionalPadding(value string, padded, raw func(string) ([]byte, error)) ([]byte, error) {
+	if strings.HasSuffix(value, "=") {
+		return padded(value)
+	}
+	return raw(value)
+}
+
+// islyDecodeBase58 decodes the Bitcoin base58 alphabet. Each leading '1' is a zero byte.
+func islyDecodeBase58(value string) ([]byte, error) {
+	n := new(big.Int)
+	digit := new(big.Int)
+
+	for i := 0; i < len(value); i++ {
+		index := base58Index[value[i]]
+		if index < 0 {
+			return nil, fmt.Errorf("invalid base58 character '%c' at offset %d", value[i], i)
+		}
+		n.Mul(n, base58Radix)
+		n.Add(n, digit.SetInt64(int64(index)))
+	}
+
+	zeros := 0
+	for zeros < len(value) && value[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
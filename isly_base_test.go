@@ -0,0 +1,145 @@
+package isly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslyParseBaseEncoding(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		encoding  string
+		expected  []byte
+		expectErr bool
+	}{
+		{
+			desc:     "base64 padded",
+			input:    "aGVsbG8=",
+			encoding: "base64",
+			expected: []byte("hello"),
+		},
+		{
+			desc:     "base64 without padding",
+			input:    "aGVsbG8",
+			encoding: "base64",
+			expected: []byte("hello"),
+		},
+		{
+			desc:     "base64 with line breaks",
+			input:    " aGVs\nbG8= ",
+			encoding: "base64",
+			expected: []byte("hello"),
+		},
+		{
+			desc:     "base64 empty",
+			input:    "",
+			encoding: "base64",
+			expected: []byte{},
+		},
+		{
+			desc:      "base64 invalid",
+			input:     "aGVs*G8=",
+			encoding:  "base64",
+			expectErr: true,
+		},
+		{
+			desc:     "base64url",
+			input:    "-_8=",
+			encoding: "base64url",
+			expected: []byte{0xFB, 0xFF},
+		},
+		{
+			desc:     "base64url without padding",
+			input:    "-_8",
+			encoding: "base64url",
+			expected: []byte{0xFB, 0xFF},
+		},
+		{
+			desc:      "base64url rejects standard alphabet",
+			input:     "+/8=",
+			encoding:  "base64url",
+			expectErr: true,
+		},
+		{
+			desc:     "rawbase64",
+			input:    "aGVsbG8",
+			encoding: "rawbase64",
+			expected: []byte("hello"),
+		},
+		{
+			desc:      "rawbase64 rejects padding",
+			input:     "aGVsbG8=",
+			encoding:  "rawbase64",
+			expectErr: true,
+		},
+		{
+			desc:     "base32 padded",
+			input:    "NBSWY3DP",
+			encoding: "base32",
+			expected: []byte("hello"),
+		},
+		{
+			desc:     "base32 lowercase without padding",
+			input:    "mzxw6",
+			encoding: "base32",
+			expected: []byte("foo"),
+		},
+		{
+			desc:     "base32 with padding",
+			input:    "MZXW6===",
+			encoding: "base32",
+			expected: []byte("foo"),
+		},
+		{
+			desc:      "base32 invalid",
+			input:     "MZXW1===",
+			encoding:  "base32",
+			expectErr: true,
+		},
+		{
+			desc:     "base58",
+			input:    "Cn8eVZg",
+			encoding: "base58",
+			expected: []byte("hello"),
+		},
+		{
+			desc:     "base58 leading zeros",
+			input:    "11Cn8eVZg",
+			encoding: "base58",
+			expected: append([]byte{0, 0}, []byte("hello")...),
+		},
+		{
+			desc:     "base58 empty",
+			input:    "",
+			encoding: "base58",
+			expected: []byte{},
+		},
+		{
+			desc:      "base58 invalid character",
+			input:     "0OIl",
+			encoding:  "base58",
+			expectErr: true,
+		},
+		{
+			desc:      "unknown encoding",
+			input:     "abc",
+			encoding:  "base85",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := islyParseBaseEncoding(tc.input, tc.encoding)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error for invalid input")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package isly
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+
+	decimalPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+)
+
+// islyIsBigNumber reports whether t is big.Int, big.Float, big.Rat or a pointer to one of them.
+func islyIsBigNumber(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == bigIntType || t == bigFloatType || t == bigRatType
+}
+
+// islyParseBigNumber parses value into a math/big field without going through float64.
+// big.Float precision comes from the `prec=` tag option (in bits) or is chosen to hold
+// every digit of value.
+func islyParseBigNumber(field reflect.Value, value string, tag islyTag) error {
+	value = strings.TrimSpace(value)
+	fieldType := field.Type()
+
+	isPtr := fieldType.Kind() == reflect.Ptr
+	if isPtr {
+		fieldType = fieldType.Elem()
+	}
+
+	if value == "" {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	var parsed reflect.Value
+
+	switch fieldType {
+	case bigIntType:
+		n, ok := islyParseBigInt(value)
+		if !ok {
+			return fmt.Errorf("failed to parse big integer value '%s'", value)
+		}
+		parsed = reflect.ValueOf(n)
+
+	case bigFloatType:
+		prec := uint(0)
+		if raw := tag.option("prec"); raw != "" {
+			p, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid prec '%s': %w", raw, err)
+			}
+			prec = uint(p)
+		} else {
+			// ~3.33 bits per decimal digit, never less than float64
+			prec = uint(math.Max(64, math.Ceil(float64(len(value))*math.Log2(10))))
+		}
+
+		f, _, err := big.ParseFloat(value, 0, prec, big.ToNearestEven)
+		if err != nil {
+			return fmt.Errorf("failed to parse big float value '%s': %w", value, err)
+		}
+		parsed = reflect.ValueOf(f)
+
+	case bigRatType:
+		r, ok := islyParseBigRat(value)
+		if !ok {
+			return fmt.Errorf("failed to parse rational value '%s'", value)
+		}
+		parsed = reflect.ValueOf(r)
+	}
+
+	if isPtr {
+		field.Set(parsed)
+	} else {
+		field.Set(parsed.Elem())
+	}
+
+	return nil
+}
+
+// islyParseBigInt parses a base 10 integer, or a base 16 one with an explicit 0x prefix.
+// A leading zero is not an octal prefix as with big.Int.SetString and base 0, so
+// zero-padded values like "0100" keep their decimal value.
+func islyParseBigInt(value string) (*big.Int, bool) {
+	sign, digits := "", value
+	if strings.HasPrefix(digits, "+") || strings.HasPrefix(digits, "-") {
+		sign, digits = digits[:1], digits[1:]
+	}
+
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		base, digits = 16, digits[2:]
+	}
+
+	// SetString would accept a second sign after the one taken above
+	if digits == "" || digits[0] == '+' || digits[0] == '-' {
+		return nil, false
+	}
+
+	return new(big.Int).SetString(sign+digits, base)
+}
+
+// islyParseBigRat parses a fraction ("3/4") with the integer rules of islyParseBigInt,
+// or a decimal number ("0.75", "1e-3").
+func islyParseBigRat(value string) (*big.Rat, bool) {
+	numerator, denominator, isFraction := strings.Cut(value, "/")
+	if !isFraction {
+		return new(big.Rat).SetString(value)
+	}
+
+	a, ok := islyParseBigInt(numerator)
+	if !ok {
+		return nil, false
+	}
+	b, ok := islyParseBigInt(denominator)
+	if !ok || b.Sign() == 0 {
+		return nil, false
+	}
+
+	return new(big.Rat).SetFrac(a, b), true
+}
+
+// islyNormalizeDecimal validates a plain decimal ("-1234.50") and returns it trimmed,
+// without a leading "+", so it can be stored in a string-backed field unchanged.
+func islyNormalizeDecimal(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	if !decimalPattern.MatchString(value) {
+		return "", fmt.Errorf("failed to parse decimal value '%s'", value)
+	}
+
+	return strings.TrimPrefix(value, "+"), nil
+}
+
+// islyParseScaledDecimal turns a decimal into an integer count of 10^-scale units,
+// "12.34" with scale 2 is 1234. Digits that don't fit the scale are an error rather
+// than being rounded away.
+func islyParseScaledDecimal(value string, scale int) (string, error) {
+	decimal, err := islyNormalizeDecimal(value)
+	if err != nil || decimal == "" {
+		return decimal, err
+	}
+
+	sign := ""
+	if strings.HasPrefix(decimal, "-") {
+		sign = "-"
+		decimal = decimal[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(decimal, ".")
+	fracPart = strings.TrimRight(fracPart, "0")
+
+	if len(fracPart) > scale {
+		return "", fmt.Errorf("decimal value '%s' has more than %d fractional digits", value, scale)
+	}
+
+	digits := strings.TrimLeft(intPart+fracPart+strings.Repeat("0", scale-len(fracPart)), "0")
+	if digits == "" {
+		return "0", nil
+	}
+
+	return sign + digits, nil
+}
+
+// islyParseDecimalField handles the `decimal` tag type and the `scale=` option. With a scale
+// the value is stored as a scaled integer, otherwise it is kept as validated text in a
+// string-backed field or parsed into a math/big field.
+func islyParseDecimalField(field reflect.Value, value string, tag islyTag) error {
+	fieldType := field.Type()
+
+	if rawScale := tag.option("scale"); rawScale != "" {
+		scale, err := strconv.Atoi(rawScale)
+		if err != nil || scale < 0 {
+			return fmt.Errorf("invalid scale '%s'", rawScale)
+		}
+
+		scaled, err := islyParseScaledDecimal(value, scale)
+		if err != nil {
+			return err
+		}
+
+		if islyIsBigNumber(fieldType) {
+			return islyParseBigNumber(field, scaled, tag)
+		}
+
+		switch fieldType.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return islyParsePrimitiveData(field, scaled, fieldType, "")
+		}
+		return fmt.Errorf("scale requires an integer field, got %v", fieldType)
+	}
+
+	decimal, err := islyNormalizeDecimal(value)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case fieldType.Kind() == reflect.String:
+		field.SetString(decimal)
+		return nil
+	case islyIsBigNumber(fieldType):
+		return islyParseBigNumber(field, decimal, tag)
+	}
+
+	return fmt.Errorf("decimal tag requires a string, math/big or integer field with scale, got %v", fieldType)
+}
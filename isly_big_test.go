@@ -0,0 +1,301 @@
+package isly
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslyParseBigNumber(t *testing.T) {
+	hugeInt, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	testCases := []struct {
+		desc      string
+		fieldType reflect.Type
+		value     string
+		tag       islyTag
+		expectErr bool
+		validate  func(t *testing.T, value reflect.Value)
+	}{
+		{
+			desc:      "big.Int beyond int64",
+			fieldType: reflect.TypeOf(big.Int{}),
+			value:     "123456789012345678901234567890",
+			validate: func(t *testing.T, value reflect.Value) {
+				n := value.Interface().(big.Int)
+				assert.Equal(t, 0, hugeInt.Cmp(&n))
+			},
+		},
+		{
+			desc:      "*big.Int hex",
+			fieldType: reflect.TypeOf(&big.Int{}),
+			value:     "0xff",
+			validate: func(t *testing.T, value reflect.Value) {
+				assert.Equal(t, int64(255), value.Interface().(*big.Int).Int64())
+			},
+		},
+		{
+			desc:      "big.Int zero-padded is decimal",
+			fieldType: reflect.TypeOf(big.Int{}),
+			value:     "0100",
+			validate: func(t *testing.T, value reflect.Value) {
+				n := value.Interface().(big.Int)
+				assert.Equal(t, int64(100), n.Int64())
+			},
+		},
+		{
+			desc:      "big.Int zero-padded with 8 and 9",
+			fieldType: reflect.TypeOf(big.Int{}),
+			value:     "-0089",
+			validate: func(t *testing.T, value reflect.Value) {
+				n := value.Interface().(big.Int)
+				assert.Equal(t, int64(-89), n.Int64())
+			},
+		},
+		{
+			desc:      "*big.Int zero-padded",
+			fieldType: reflect.TypeOf(&big.Int{}),
+			value:     "007",
+			validate: func(t *testing.T, value reflect.Value) {
+				assert.Equal(t, int64(7), value.Interface().(*big.Int).Int64())
+			},
+		},
+		{
+			desc:      "big.Int octal and binary prefixes are not accepted",
+			fieldType: reflect.TypeOf(big.Int{}),
+			value:     "0o17",
+			expectErr: true,
+		},
+		{
+			desc:      "big.Int double sign",
+			fieldType: reflect.TypeOf(big.Int{}),
+			value:     "-0x-1",
+			expectErr: true,
+		},
+		{
+			desc:      "*big.Int empty is nil",
+			fieldType: reflect.TypeOf(&big.Int{}),
+			value:     "  ",
+			validate: func(t *testing.T, value reflect.Value) {
+				assert.Nil(t, value.Interface().(*big.Int))
+			},
+		},
+		{
+			desc:      "big.Int invalid",
+			fieldType: reflect.TypeOf(big.Int{}),
+			value:     "12.5",
+			expectErr: true,
+		},
+		{
+			desc:      "big.Float keeps every digit",
+			fieldType: reflect.TypeOf(&big.Float{}),
+			value:     "1234567890.12345678901234567890",
+			validate: func(t *testing.T, value reflect.Value) {
+				f := value.Interface().(*big.Float)
+				assert.Equal(t, "1234567890.12345678901234567890", f.Text('f', 20))
+			},
+		},
+		{
+			desc:      "big.Float with prec",
+			fieldType: reflect.TypeOf(big.Float{}),
+			value:     "0.1",
+			tag:       islyTag{options: map[string]string{"prec": "200"}},
+			validate: func(t *testing.T, value reflect.Value) {
+				f := value.Interface().(big.Float)
+				assert.Equal(t, uint(200), f.Prec())
+			},
+		},
+		{
+			desc:      "big.Float invalid",
+			fieldType: reflect.TypeOf(big.Float{}),
+			value:     "one",
+			expectErr: true,
+		},
+		{
+			desc:      "big.Rat decimal",
+			fieldType: reflect.TypeOf(&big.Rat{}),
+			value:     "0.10",
+			validate: func(t *testing.T, value reflect.Value) {
+				assert.Equal(t, "1/10", value.Interface().(*big.Rat).String())
+			},
+		},
+		{
+			desc:      "big.Rat fraction",
+			fieldType: reflect.TypeOf(big.Rat{}),
+			value:     "3/4",
+			validate: func(t *testing.T, value reflect.Value) {
+				r := value.Interface().(big.Rat)
+				assert.Equal(t, "3/4", r.String())
+			},
+		},
+		{
+			desc:      "big.Rat zero-padded fraction",
+			fieldType: reflect.TypeOf(big.Rat{}),
+			value:     "0100/003",
+			validate: func(t *testing.T, value reflect.Value) {
+				r := value.Interface().(big.Rat)
+				assert.Equal(t, "100/3", r.String())
+			},
+		},
+		{
+			desc:      "big.Rat invalid",
+			fieldType: reflect.TypeOf(big.Rat{}),
+			value:     "3/0",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fieldValue := reflect.New(tc.fieldType).Elem()
+
+			err := islyParseBigNumber(fieldValue, tc.value, tc.tag)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				tc.validate(t, fieldValue)
+			}
+		})
+	}
+}
+
+func TestIslyParseDecimalField(t *testing.T) {
+	type Amount string
+
+	testCases := []struct {
+		desc      string
+		fieldType reflect.Type
+		value     string
+		tag       string
+		expected  interface{}
+		expectErr bool
+	}{
+		{
+			desc:      "string-backed decimal",
+			fieldType: reflect.TypeOf(""),
+			value:     " 1234.50 ",
+			tag:       "amount, decimal",
+			expected:  "1234.50",
+		},
+		{
+			desc:      "named string decimal drops plus sign",
+			fieldType: reflect.TypeOf(Amount("")),
+			value:     "+0.000000000000000001",
+			tag:       "amount, decimal",
+			expected:  Amount("0.000000000000000001"),
+		},
+		{
+			desc:      "string-backed decimal invalid",
+			fieldType: reflect.TypeOf(""),
+			value:     "12.3.4",
+			tag:       "amount, decimal",
+			expectErr: true,
+		},
+		{
+			desc:      "scaled int64",
+			fieldType: reflect.TypeOf(int64(0)),
+			value:     "12.34",
+			tag:       "amount, scale=2",
+			expected:  int64(1234),
+		},
+		{
+			desc:      "scaled int64 pads missing digits",
+			fieldType: reflect.TypeOf(int64(0)),
+			value:     "12.3",
+			tag:       "amount, scale=2",
+			expected:  int64(1230),
+		},
+		{
+			desc:      "scaled int64 whole number",
+			fieldType: reflect.TypeOf(int64(0)),
+			value:     "-7",
+			tag:       "amount, scale=2",
+			expected:  int64(-700),
+		},
+		{
+			desc:      "scaled int64 ignores trailing zeros",
+			fieldType: reflect.TypeOf(int64(0)),
+			value:     "1.5000",
+			tag:       "amount, scale=2",
+			expected:  int64(150),
+		},
+		{
+			desc:      "scaled int64 would round",
+			fieldType: reflect.TypeOf(int64(0)),
+			value:     "12.345",
+			tag:       "amount, scale=2",
+			expectErr: true,
+		},
+		{
+			desc:      "scaled int64 overflow",
+			fieldType: reflect.TypeOf(int64(0)),
+			value:     "99999999999999999.99",
+			tag:       "amount, scale=2",
+			expectErr: true,
+		},
+		{
+			desc:      "scaled uint",
+			fieldType: reflect.TypeOf(uint32(0)),
+			value:     ".5",
+			tag:       "amount, decimal, scale=3",
+			expected:  uint32(500),
+		},
+		{
+			desc:      "scaled zero",
+			fieldType: reflect.TypeOf(int64(0)),
+			value:     "0.00",
+			tag:       "amount, scale=2",
+			expected:  int64(0),
+		},
+		{
+			desc:      "scaled big.Int",
+			fieldType: reflect.TypeOf(big.Int{}),
+			value:     "123456789012345678901234567890.12",
+			tag:       "amount, scale=2",
+			expected: func() big.Int {
+				n, _ := new(big.Int).SetString("12345678901234567890123456789012", 10)
+				return *n
+			}(),
+		},
+		{
+			desc:      "scale on a float field",
+			fieldType: reflect.TypeOf(float64(0)),
+			value:     "1.5",
+			tag:       "amount, scale=2",
+			expectErr: true,
+		},
+		{
+			desc:      "invalid scale",
+			fieldType: reflect.TypeOf(int64(0)),
+			value:     "1.5",
+			tag:       "amount, scale=-1",
+			expectErr: true,
+		},
+		{
+			desc:      "decimal into float is refused",
+			fieldType: reflect.TypeOf(float64(0)),
+			value:     "1.5",
+			tag:       "amount, decimal",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fieldValue := reflect.New(tc.fieldType).Elem()
+
+			err := islyParseDecimalField(fieldValue, tc.value, islyParseTag(tc.tag))
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, fieldValue.Interface())
+			}
+		})
+	}
+}
@@ -1,21 +1,48 @@
 package isly
 
 import (
+	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 )
 
-func islyParseBinary(value string) []byte {
-	value = strings.TrimPrefix(strings.TrimSpace(value), "b'")
-	value = strings.TrimSuffix(value, "'")
+// islyParseBinary decodes "b'1010'", "0b1010" or plain bit strings. Spaces, "_" and ":"
+// may separate groups of bits.
+//
+// By default the bits are cut into bytes from the left and only the last byte is
+// left-padded, so "b'1010101110'" is {0xAB, 0x02}. In numeric mode the whole value is
+// left-padded as a big-endian number, so the same input is {0x02, 0xAE}.
+func islyParseBinary(value string, numeric bool) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	original := value
+
+	switch {
+	case strings.HasPrefix(value, "b'"):
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "b'"), "'")
+	case strings.HasPrefix(value, "0b"), strings.HasPrefix(value, "0B"):
+		value = value[2:]
+	}
+
+	value = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' || r == ':' {
+			return -1
+		}
+		return r
+	}, value)
 
 	length := len(value)
 	if length == 0 {
-		return []byte{}
+		return []byte{}, nil
+	}
+
+	if numeric && length%8 != 0 {
+		value = strings.Repeat("0", 8-length%8) + value
+		length = len(value)
 	}
 
 	// convert 8 byte-> 1 byte
-	var result []byte
+	result := make([]byte, 0, (length+7)/8)
 	for i := 0; i < length; i += 8 {
 		end := i + 8
 		if end > length {
@@ -30,11 +57,21 @@ func islyParseBinary(value string) []byte {
 		// Parse binary string -> uint64
 		b, err := strconv.ParseUint(binStr, 2, 8)
 		if err != nil {
-			return nil
+			return nil, fmt.Errorf("failed to parse binary value '%s': invalid bits '%s'", original, value[i:end])
 		}
 
 		result = append(result, byte(b))
 	}
 
-	return result
+	return result, nil
+}
+
+// islyBinaryNumeric reports whether a binary tagged field should be decoded as a number.
+func islyBinaryNumeric(fieldType reflect.Type, tag islyTag) bool {
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return tag.hasOption("numeric")
 }
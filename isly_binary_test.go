@@ -8,9 +8,11 @@ import (
 
 func TestIslyParseBinary(t *testing.T) {
 	testCases := []struct {
-		desc     string
-		input    string
-		expected []byte
+		desc      string
+		input     string
+		numeric   bool
+		expected  []byte
+		expectErr bool
 	}{
 		{
 			desc:     "empty binary string",
@@ -45,7 +47,7 @@ func TestIslyParseBinary(t *testing.T) {
 		{
 			desc:     "with spaces",
 			input:    "b' 10101010 '",
-			expected: []byte(nil),
+			expected: []byte{0xAA},
 		},
 		{
 			desc:     "different prefix format",
@@ -53,14 +55,14 @@ func TestIslyParseBinary(t *testing.T) {
 			expected: []byte{0xAA},
 		},
 		{
-			desc:     "invalid binary digits",
-			input:    "b'1010102'",
-			expected: nil,
+			desc:      "invalid binary digits",
+			input:     "b'1010102'",
+			expectErr: true,
 		},
 		{
-			desc:     "completely invalid input",
-			input:    "hello",
-			expected: nil,
+			desc:      "completely invalid input",
+			input:     "hello",
+			expectErr: true,
 		},
 		{
 			desc:     "multiple bytes with partial last byte",
@@ -82,15 +84,55 @@ func TestIslyParseBinary(t *testing.T) {
 			input:    "  b'10101010'  ",
 			expected: []byte{0xAA},
 		},
+		{
+			desc:     "0b prefix",
+			input:    "0b10101010",
+			expected: []byte{0xAA},
+		},
+		{
+			desc:     "0B prefix with underscores",
+			input:    "0B1010_1011_1000_1101",
+			expected: []byte{0xAB, 0x8D},
+		},
+		{
+			desc:     "space separated bytes",
+			input:    "10101011 10001101",
+			expected: []byte{0xAB, 0x8D},
+		},
+		{
+			desc:     "numeric - bits not divisible by 8",
+			input:    "b'1010101110'",
+			numeric:  true,
+			expected: []byte{0x02, 0xAE},
+		},
+		{
+			desc:     "numeric - full bytes are unchanged",
+			input:    "0b1010101110001101",
+			numeric:  true,
+			expected: []byte{0xAB, 0x8D},
+		},
+		{
+			desc:     "numeric - short value",
+			input:    "0b101",
+			numeric:  true,
+			expected: []byte{0x05},
+		},
+		{
+			desc:      "numeric - invalid digits",
+			input:     "0b102",
+			numeric:   true,
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			result := islyParseBinary(tc.input)
+			result, err := islyParseBinary(tc.input, tc.numeric)
 
-			if tc.expected == nil {
-				assert.Nil(t, result, "expected nil result for invalid input")
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error for invalid input")
 			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
 				assert.Equal(t, tc.expected, result, "binary parsing result mismatch")
 
 				assert.Equal(t, len(tc.expected), len(result),
@@ -0,0 +1,54 @@
+package isly
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// islySetBytes stores decoded bytes into a []byte, a [N]byte array or, read as a
+// big-endian unsigned number, an integer field.
+func islySetBytes(field reflect.Value, data []byte) error {
+	fieldType := field.Type()
+
+	switch fieldType.Kind() {
+	case reflect.Slice:
+		if fieldType.Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		field.SetBytes(data)
+		return nil
+
+	case reflect.Array:
+		if fieldType.Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		if len(data) == 0 {
+			field.Set(reflect.Zero(fieldType))
+			return nil
+		}
+		if len(data) != fieldType.Len() {
+			return fmt.Errorf("value has %d bytes, %v needs %d", len(data), fieldType, fieldType.Len())
+		}
+		reflect.Copy(field, reflect.ValueOf(data))
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := new(big.Int).SetBytes(data)
+		if n.BitLen() > fieldType.Bits() {
+			return fmt.Errorf("value 0x%x overflows %v", data, fieldType)
+		}
+		field.SetUint(n.Uint64())
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := new(big.Int).SetBytes(data)
+		if n.BitLen() > fieldType.Bits()-1 {
+			return fmt.Errorf("value 0x%x overflows %v", data, fieldType)
+		}
+		field.SetInt(n.Int64())
+		return nil
+	}
+
+	return fmt.Errorf("unsupported field type %v, expected []byte, [N]byte or an integer", fieldType)
+}
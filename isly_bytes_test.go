@@ -0,0 +1,105 @@
+package isly
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslySetBytes(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		fieldType reflect.Type
+		input     []byte
+		expected  interface{}
+		expectErr bool
+	}{
+		{
+			desc:      "byte slice",
+			fieldType: reflect.TypeOf([]byte{}),
+			input:     []byte{0xDE, 0xAD},
+			expected:  []byte{0xDE, 0xAD},
+		},
+		{
+			desc:      "byte array",
+			fieldType: reflect.TypeOf([4]byte{}),
+			input:     []byte{0xDE, 0xAD, 0xBE, 0xEF},
+			expected:  [4]byte{0xDE, 0xAD, 0xBE, 0xEF},
+		},
+		{
+			desc:      "byte array with wrong length",
+			fieldType: reflect.TypeOf([4]byte{}),
+			input:     []byte{0xDE, 0xAD},
+			expectErr: true,
+		},
+		{
+			desc:      "empty into byte array",
+			fieldType: reflect.TypeOf([2]byte{}),
+			input:     []byte{},
+			expected:  [2]byte{},
+		},
+		{
+			desc:      "uint32 big-endian",
+			fieldType: reflect.TypeOf(uint32(0)),
+			input:     []byte{0xDE, 0xAD, 0xBE, 0xEF},
+			expected:  uint32(0xDEADBEEF),
+		},
+		{
+			desc:      "uint16 with leading zero bytes",
+			fieldType: reflect.TypeOf(uint16(0)),
+			input:     []byte{0x00, 0x00, 0x01, 0x02},
+			expected:  uint16(0x0102),
+		},
+		{
+			desc:      "uint8 overflow",
+			fieldType: reflect.TypeOf(uint8(0)),
+			input:     []byte{0x01, 0x00},
+			expectErr: true,
+		},
+		{
+			desc:      "int64",
+			fieldType: reflect.TypeOf(int64(0)),
+			input:     []byte{0x1A, 0x3F},
+			expected:  int64(0x1A3F),
+		},
+		{
+			desc:      "int32 overflow",
+			fieldType: reflect.TypeOf(int32(0)),
+			input:     []byte{0xFF, 0xFF, 0xFF, 0xFF},
+			expectErr: true,
+		},
+		{
+			desc:      "empty into int",
+			fieldType: reflect.TypeOf(0),
+			input:     []byte{},
+			expected:  0,
+		},
+		{
+			desc:      "unsupported type",
+			fieldType: reflect.TypeOf(""),
+			input:     []byte{0x01},
+			expectErr: true,
+		},
+		{
+			desc:      "unsupported slice type",
+			fieldType: reflect.TypeOf([]int{}),
+			input:     []byte{0x01},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			field := reflect.New(tc.fieldType).Elem()
+			err := islySetBytes(field, tc.input)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, field.Interface())
+			}
+		})
+	}
+}
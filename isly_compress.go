@@ -0,0 +1,179 @@
+package isly
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Magic bytes of the compressed formats ReadFile recognizes
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh") // followed by the block size, '1' to '9'
+
+	zipMagic  = []byte("PK\x03\x04")
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// islyByteCounter reads a source and counts the bytes read from it, before any
+// decompression, for progress reporting. file is nil for a stream.
+type islyByteCounter struct {
+	file   *os.File
+	stream io.Reader
+	n      int64
+}
+
+func islyCountFile(file *os.File) *islyByteCounter {
+	return &islyByteCounter{file: file, stream: file}
+}
+
+func islyCountStream(stream io.Reader) *islyByteCounter {
+	return &islyByteCounter{stream: stream}
+}
+
+func (c *islyByteCounter) Read(p []byte) (int, error) {
+	n, err := c.stream.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *islyByteCounter) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.file.ReadAt(p, off)
+	c.n += int64(n)
+	return n, err
+}
+
+// islyDecompress returns a reader of the CSV text in input.file. gzip, bzip2, zstd and
+// zip input is detected by its magic bytes, or by the extension of name when the file is
+// too short to tell. The closer, when not nil, must be closed before the file.
+func islyDecompress(input *islyByteCounter, name string, zipEntry string) (io.Reader, io.Closer, error) {
+	// the magic bytes are read again by the decompressor, so they are not counted
+	magic := make([]byte, 4)
+	n, err := input.file.ReadAt(magic, 0)
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	magic = magic[:n]
+
+	format := ""
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		format = "gzip"
+	case bytes.HasPrefix(magic, bzip2Magic) && len(magic) == 4 && magic[3] >= '1' && magic[3] <= '9':
+		format = "bzip2"
+	case bytes.HasPrefix(magic, zipMagic):
+		format = "zip"
+	case bytes.HasPrefix(magic, zstdMagic):
+		format = "zstd"
+	case n < len(zipMagic):
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".gz", ".gzip":
+			format = "gzip"
+		case ".bz2":
+			format = "bzip2"
+		case ".zip":
+			format = "zip"
+		case ".zst":
+			format = "zstd"
+		}
+	}
+
+	switch format {
+	case "gzip":
+		gz, err := gzip.NewReader(input)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid gzip input: %w", err)
+		}
+		return gz, gz, nil
+
+	case "bzip2":
+		return bzip2.NewReader(input), nil, nil
+
+	case "zip":
+		return islyOpenZipEntry(input, zipEntry)
+
+	case "zstd":
+		// one file is decoded at a time, no need for the decoder's worker goroutines
+		zr, err := zstd.NewReader(input, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid zstd input: %w", err)
+		}
+		rc := zr.IOReadCloser()
+		return rc, rc, nil
+	}
+
+	return input, nil, nil
+}
+
+// islyOpenZipEntry opens the entry of the zip archive in input selected by pattern, a
+// name or a path.Match glob checked against the full entry name and its base name.
+// Without a pattern the archive must hold a single file, or a single .csv file.
+func islyOpenZipEntry(input *islyByteCounter, pattern string) (io.Reader, io.Closer, error) {
+	info, err := input.file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	archive, err := zip.NewReader(input, info.Size())
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid zip input: %w", err)
+	}
+
+	var files, matches []*zip.File
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		files = append(files, entry)
+
+		if pattern == "" {
+			if strings.EqualFold(path.Ext(entry.Name), ".csv") {
+				matches = append(matches, entry)
+			}
+			continue
+		}
+
+		fullMatch, err := path.Match(pattern, entry.Name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid zip entry pattern '%s': %w", pattern, err)
+		}
+		baseMatch, _ := path.Match(pattern, path.Base(entry.Name))
+		if fullMatch || baseMatch {
+			matches = append(matches, entry)
+		}
+	}
+
+	if pattern == "" && len(files) == 1 {
+		matches = files
+	}
+
+	switch len(matches) {
+	case 0:
+		if pattern == "" {
+			return nil, nil, fmt.Errorf("zip archive has no csv file")
+		}
+		return nil, nil, fmt.Errorf("no zip entry matches '%s'", pattern)
+	case 1:
+	default:
+		names := make([]string, len(matches))
+		for j, entry := range matches {
+			names[j] = entry.Name
+		}
+		return nil, nil, fmt.Errorf("zip archive has several csv files (%s), select one with WithZipEntry", strings.Join(names, ", "))
+	}
+
+	entry, err := matches[0].Open()
+	if err != nil {
+		return nil, nil, fmt.Errorf("zip entry '%s': %w", matches[0].Name, err)
+	}
+	return entry, entry, nil
+}
@@ -0,0 +1,206 @@
+package isly
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+const compressTestCSV = "id,name\n1,John\n2,Jane\n"
+
+// bzip2 -9 of compressTestCSV, the standard library has no bzip2 writer
+var compressTestBzip2 = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x87, 0xad,
+	0x8b, 0x16, 0x00, 0x00, 0x08, 0x5d, 0x00, 0x00, 0x10, 0x00, 0x04, 0x30,
+	0x00, 0x00, 0x10, 0x26, 0x63, 0xa0, 0x00, 0x22, 0x26, 0x8d, 0x93, 0x51,
+	0xe9, 0x08, 0x06, 0x80, 0x2d, 0xc8, 0x08, 0x41, 0x83, 0x7e, 0x7c, 0xc2,
+	0xf1, 0x6d, 0x7c, 0x5d, 0xc9, 0x14, 0xe1, 0x42, 0x42, 0x1e, 0xb6, 0x2c,
+	0x58,
+}
+
+func gzipTestData(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatalf("failed to gzip: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("failed to gzip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func zstdTestData(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw, err := zstd.NewWriter(&buf)
+	if err != nil {
+		t.Fatalf("failed to zstd: %v", err)
+	}
+	if _, err := zw.Write([]byte(content)); err != nil {
+		t.Fatalf("failed to zstd: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to zstd: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func zipTestData(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to zip: %v", err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to zip: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadFileCompressed(t *testing.T) {
+	type Person struct {
+		ID   int    `isly:"id"`
+		Name string `isly:"name"`
+	}
+
+	expected := []Person{{1, "John"}, {2, "Jane"}}
+	other := "id,name\n9,Other\n"
+
+	testCases := []struct {
+		desc      string
+		name      string
+		content   []byte
+		options   []Option
+		expected  []Person
+		expectErr bool
+	}{
+		{
+			desc:     "gzip",
+			name:     "people.csv.gz",
+			content:  gzipTestData(t, compressTestCSV),
+			expected: expected,
+		},
+		{
+			desc:     "gzip detected without extension",
+			name:     "people.csv",
+			content:  gzipTestData(t, compressTestCSV),
+			expected: expected,
+		},
+		{
+			desc:     "bzip2",
+			name:     "people.csv.bz2",
+			content:  compressTestBzip2,
+			expected: expected,
+		},
+		{
+			desc:     "zip with a single file",
+			name:     "people.zip",
+			content:  zipTestData(t, map[string]string{"export/people.csv": compressTestCSV}),
+			expected: expected,
+		},
+		{
+			desc:     "zip picks the only csv file",
+			name:     "people.zip",
+			content:  zipTestData(t, map[string]string{"people.csv": compressTestCSV, "README.txt": "notes"}),
+			expected: expected,
+		},
+		{
+			desc:      "zip with several csv files needs a selection",
+			name:      "people.zip",
+			content:   zipTestData(t, map[string]string{"people.csv": compressTestCSV, "other.csv": other}),
+			expectErr: true,
+		},
+		{
+			desc:     "zip entry by name",
+			name:     "people.zip",
+			content:  zipTestData(t, map[string]string{"people.csv": compressTestCSV, "other.csv": other}),
+			options:  []Option{WithZipEntry("people.csv")},
+			expected: expected,
+		},
+		{
+			desc:     "zip entry by glob on the base name",
+			name:     "people.zip",
+			content:  zipTestData(t, map[string]string{"2024/people_01.csv": compressTestCSV, "2024/other.csv": other}),
+			options:  []Option{WithZipEntry("people_*.csv")},
+			expected: expected,
+		},
+		{
+			desc:      "zip entry not found",
+			name:      "people.zip",
+			content:   zipTestData(t, map[string]string{"people.csv": compressTestCSV}),
+			options:   []Option{WithZipEntry("missing.csv")},
+			expectErr: true,
+		},
+		{
+			desc:     "zstd",
+			name:     "people.csv.zst",
+			content:  zstdTestData(t, compressTestCSV),
+			expected: expected,
+		},
+		{
+			desc:     "zstd detected without extension",
+			name:     "people.csv",
+			content:  zstdTestData(t, compressTestCSV),
+			expected: expected,
+		},
+		{
+			desc:      "corrupt zstd",
+			name:      "people.csv.zst",
+			content:   []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0x00},
+			expectErr: true,
+		},
+		{
+			desc:      "corrupt gzip",
+			name:      "people.csv.gz",
+			content:   []byte{0x1f, 0x8b, 0x00},
+			expectErr: true,
+		},
+		{
+			desc:     "plain csv",
+			name:     "people.csv",
+			content:  []byte(compressTestCSV),
+			expected: expected,
+		},
+		{
+			desc:     "plain csv starting with the bzip2 signature",
+			name:     "people.csv",
+			content:  []byte("BZhx,id,name\nx,1,John\nx,2,Jane\n"),
+			expected: expected,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			component := NewIsly(tc.options...)
+			err := component.ReadFile(writeTestFile(t, tc.name, tc.content))
+
+			var people []Person
+			if err == nil {
+				err = component.UnmarshalCSV(&people)
+			}
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, people)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package isly
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalCSVContext(t *testing.T) {
+	type Item struct {
+		ID int `isly:"id"`
+	}
+
+	const input = "id\n1\n2\n3\n4\n5\n"
+
+	t.Run("cancelled before decoding", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		component := NewIsly()
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, input)))
+
+		var items []Item
+		err := component.UnmarshalCSVContext(ctx, &items)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, items)
+	})
+
+	t.Run("cancelled between rows", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		component := NewIsly(WithProgress(func(rows int, bytesRead int64) {
+			if rows == 2 {
+				cancel()
+			}
+		}))
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, input)))
+
+		var items []Item
+		err := component.UnmarshalCSVContext(ctx, &items)
+		assert.True(t, errors.Is(err, context.Canceled), "expected context.Canceled, got: %v", err)
+		assert.EqualError(t, err, "decoding stopped after 2 rows: context canceled")
+	})
+
+	t.Run("progress reports rows and bytes", func(t *testing.T) {
+		var rows []int
+		var offsets []int64
+
+		component := NewIsly(WithProgress(func(n int, bytesRead int64) {
+			rows = append(rows, n)
+			offsets = append(offsets, bytesRead)
+		}))
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, input)))
+
+		var items []Item
+		assert.NoError(t, component.UnmarshalCSVContext(context.Background(), &items))
+		assert.Len(t, items, 5)
+
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, rows)
+		assert.IsNonDecreasing(t, offsets)
+		assert.Equal(t, int64(len(input)), offsets[len(offsets)-1])
+	})
+
+	t.Run("progress counts compressed bytes", func(t *testing.T) {
+		compressed := gzipTestData(t, "id\n"+strings.Repeat("12345\n", 20000))
+
+		var last int64
+		component := NewIsly(WithProgress(func(n int, bytesRead int64) { last = bytesRead }))
+		assert.NoError(t, component.ReadFile(writeTestFile(t, "items.csv.gz", compressed)))
+
+		var items []Item
+		assert.NoError(t, component.UnmarshalCSV(&items))
+		assert.Len(t, items, 20000)
+		assert.Equal(t, int64(len(compressed)), last)
+	})
+
+	t.Run("cancelled while rows are filtered out", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		filtered := 0
+		component := NewIsly(WithRowFilter(func(record []string) bool {
+			filtered++
+			if filtered == 3 {
+				cancel()
+			}
+			return false
+		}))
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, input)))
+
+		var items []Item
+		err := component.UnmarshalCSVContext(ctx, &items)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 3, filtered)
+	})
+}
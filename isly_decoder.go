@@ -0,0 +1,109 @@
+package isly
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Decoder holds the options and the per-type field plans shared by the components and
+// readers created from it. It never changes after NewDecoder, so one Decoder can be
+// configured at startup and used from many goroutines. Functions passed as options
+// (row filter, cell transform, progress) are called concurrently in that case.
+type Decoder struct {
+	options islyOptions
+
+	// reflect.Type -> *islyStructPlan
+	plans sync.Map
+}
+
+// islyStructPlan lists the tagged fields of a struct type with their parsed tags, so
+// tags are parsed once per type instead of once per row.
+type islyStructPlan struct {
+	fields []islyFieldPlan
+	// columns bound to a field
+	bound map[string]bool
+}
+
+type islyFieldPlan struct {
+	index int
+	tag   islyTag
+	// metadata kind (source, line, raw, extra, remain) of a field with an `isly:",kind"` tag
+	meta string
+}
+
+// NewDecoder returns a Decoder configured with opts.
+func NewDecoder(opts ...Option) *Decoder {
+	d := &Decoder{}
+	for _, opt := range opts {
+		opt(&d.options)
+	}
+	return d
+}
+
+// NewIsly returns a file based component that uses the configuration of d.
+func (d *Decoder) NewIsly() IISLYComponent {
+	return &newIslyComponent{decoder: d}
+}
+
+// NewReader returns a Reader that decodes the CSV stream r with the configuration of d.
+func (d *Decoder) NewReader(r io.Reader) *Reader {
+	return &Reader{decoder: d, source: r}
+}
+
+// plan returns the cached plan of structType, building it on first use.
+func (d *Decoder) plan(structType reflect.Type) *islyStructPlan {
+	if cached, ok := d.plans.Load(structType); ok {
+		return cached.(*islyStructPlan)
+	}
+
+	plan := &islyStructPlan{bound: make(map[string]bool)}
+	for j := 0; j < structType.NumField(); j++ {
+		structField := structType.Field(j)
+
+		// Skip unexported and untagged fields
+		if !structField.IsExported() {
+			continue
+		}
+		tag := structField.Tag.Get("isly")
+		if tag == "" {
+			continue
+		}
+
+		parsedTag := islyParseTag(tag)
+		planned := islyFieldPlan{index: j, tag: parsedTag}
+		if parsedTag.name == "" && islyMetadataKinds[parsedTag.kind] {
+			planned.meta = parsedTag.kind
+		} else {
+			plan.bound[parsedTag.name] = true
+		}
+
+		plan.fields = append(plan.fields, planned)
+	}
+
+	actual, _ := d.plans.LoadOrStore(structType, plan)
+	return actual.(*islyStructPlan)
+}
+
+// Reader decodes a single CSV stream. It is not safe for concurrent use; create one
+// Reader per stream from a shared Decoder instead.
+type Reader struct {
+	decoder *Decoder
+	source  io.Reader
+}
+
+// UnmarshalCSV decodes the stream into results, a pointer to a struct or to a slice of
+// structs.
+func (r *Reader) UnmarshalCSV(results interface{}) error {
+	return r.UnmarshalCSVContext(context.Background(), results)
+}
+
+// UnmarshalCSVContext works like UnmarshalCSV and stops with ctx.Err() as soon as ctx is
+// cancelled.
+func (r *Reader) UnmarshalCSVContext(ctx context.Context, results interface{}) error {
+	sourceRead := islyCountStream(r.source)
+	return r.decoder.decode(ctx, []islySource{{
+		open: func() (io.Reader, io.Closer, *islyByteCounter, error) { return sourceRead, nil, sourceRead, nil },
+	}}, results)
+}
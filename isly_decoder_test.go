@@ -0,0 +1,116 @@
+package isly
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoderConcurrentReaders(t *testing.T) {
+	type Item struct {
+		ID   int      `isly:"id"`
+		Tags []string `isly:"tags, list, sep=|"`
+	}
+
+	decoder := NewDecoder(WithCellTransform(func(column, value string) string {
+		return strings.TrimSpace(value)
+	}))
+
+	const workers = 16
+
+	var wg sync.WaitGroup
+	errs := make([]error, workers)
+	results := make([][]Item, workers)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			input := fmt.Sprintf("id,tags\n %d ,a|b\n%d,c\n", w, w+100)
+			errs[w] = decoder.NewReader(strings.NewReader(input)).UnmarshalCSV(&results[w])
+		}(w)
+	}
+	wg.Wait()
+
+	for w := 0; w < workers; w++ {
+		assert.NoError(t, errs[w])
+		assert.Equal(t, []Item{{ID: w, Tags: []string{"a", "b"}}, {ID: w + 100, Tags: []string{"c"}}}, results[w])
+	}
+}
+
+func TestDecoderNewIsly(t *testing.T) {
+	type Item struct {
+		ID int `isly:"id"`
+	}
+
+	decoder := NewDecoder(SkipFooter(1))
+
+	for _, input := range []string{"id\n1\nTotal\n", "id\n2\n3\nTotal\n"} {
+		component := decoder.NewIsly()
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, input)))
+
+		var items []Item
+		assert.NoError(t, component.UnmarshalCSV(&items))
+		assert.NotEmpty(t, items)
+	}
+}
+
+func TestDecoderPlanCache(t *testing.T) {
+	type Item struct {
+		ID       int    `isly:"id, min=1"`
+		Name     string `isly:"name"`
+		Ignored  string
+		internal string `isly:"internal"`
+	}
+
+	decoder := NewDecoder()
+	itemType := reflect.TypeOf(Item{})
+
+	plan := decoder.plan(itemType)
+	assert.Same(t, plan, decoder.plan(itemType))
+
+	if assert.Len(t, plan.fields, 2) {
+		assert.Equal(t, 0, plan.fields[0].index)
+		assert.Equal(t, islyTag{name: "id", options: map[string]string{"min": "1"}}, plan.fields[0].tag)
+		assert.Equal(t, 1, plan.fields[1].index)
+	}
+
+	// plans are per decoder
+	assert.NotSame(t, plan, NewDecoder().plan(itemType))
+}
+
+func TestDecoderResultsType(t *testing.T) {
+	type Item struct {
+		ID int `isly:"id"`
+	}
+
+	testCases := []struct {
+		desc      string
+		results   interface{}
+		expectErr bool
+	}{
+		{desc: "slice of structs", results: &[]Item{}},
+		{desc: "struct", results: &Item{}},
+		{desc: "not a pointer", results: []Item{}, expectErr: true},
+		{desc: "slice of ints", results: &[]int{}, expectErr: true},
+		{desc: "slice of struct pointers", results: &[]*Item{}, expectErr: true},
+		{desc: "pointer to a pointer", results: new(*Item), expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := NewDecoder().NewReader(strings.NewReader("id\n1\n")).UnmarshalCSV(tc.results)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package isly
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DuplicateHeaderMode tells how a header name that appears more than once is bound.
+type DuplicateHeaderMode int
+
+const (
+	// DuplicateHeaderLast binds a name to its last column, the default
+	DuplicateHeaderLast DuplicateHeaderMode = iota
+	// DuplicateHeaderError fails the decode on the first duplicate name
+	DuplicateHeaderError
+	// DuplicateHeaderRename keeps the first column as name and renames the others
+	// name_2, name_3, ... so each can be bound by its own tag
+	DuplicateHeaderRename
+)
+
+// DuplicateHeaders sets how duplicate header names are handled. Whatever the mode, a
+// slice field tagged `isly:"name, all"` collects every column called name.
+func DuplicateHeaders(mode DuplicateHeaderMode) Option {
+	return func(o *islyOptions) {
+		o.duplicateHeaders = mode
+	}
+}
+
+// islyHeader is the header of a source once duplicate names are handled.
+type islyHeader struct {
+	// header names, renamed in DuplicateHeaderRename mode
+	names []string
+	// column of each name
+	index map[string]int
+	// every column of the names that appear more than once, by original name
+	duplicates map[string][]int
+}
+
+func islyBuildHeader(header []string, mode DuplicateHeaderMode) (*islyHeader, error) {
+	built := &islyHeader{
+		names:      header,
+		index:      make(map[string]int, len(header)),
+		duplicates: make(map[string][]int),
+	}
+
+	positions := make(map[string][]int, len(header))
+	for j, name := range header {
+		positions[name] = append(positions[name], j)
+	}
+
+	for j, name := range header {
+		if len(positions[name]) == 1 {
+			built.index[name] = j
+			continue
+		}
+		built.duplicates[name] = positions[name]
+
+		switch mode {
+		case DuplicateHeaderError:
+			columns := make([]string, len(positions[name]))
+			for k, position := range positions[name] {
+				columns[k] = strconv.Itoa(position + 1)
+			}
+			return nil, fmt.Errorf("duplicate header '%s' in columns %s", name, strings.Join(columns, ", "))
+
+		case DuplicateHeaderRename:
+			// indexed below from the renamed header
+
+		default:
+			built.index[name] = j
+		}
+	}
+
+	if mode == DuplicateHeaderRename && len(built.duplicates) > 0 {
+		built.names = islyRenameDuplicates(header)
+		for j, name := range built.names {
+			built.index[name] = j
+		}
+	}
+
+	return built, nil
+}
+
+// islyRenameDuplicates names the second and later columns of a name name_2, name_3, ...
+// skipping suffixes already used by another column.
+func islyRenameDuplicates(header []string) []string {
+	names := make([]string, len(header))
+	taken := make(map[string]bool, len(header))
+	for _, name := range header {
+		taken[name] = true
+	}
+
+	seen := make(map[string]int, len(header))
+	for j, name := range header {
+		seen[name]++
+		if seen[name] == 1 {
+			names[j] = name
+			continue
+		}
+
+		suffix := seen[name]
+		renamed := fmt.Sprintf("%s_%d", name, suffix)
+		for taken[renamed] {
+			suffix++
+			renamed = fmt.Sprintf("%s_%d", name, suffix)
+		}
+		taken[renamed] = true
+		names[j] = renamed
+	}
+	return names
+}
+
+// islyParseColumns fills the slice field with the cells at indices, each converted and
+// validated with tag. It returns the cell that failed, if any.
+func islyParseColumns(field reflect.Value, record []string, indices []int, tag islyTag) (string, error) {
+	if field.Kind() != reflect.Slice {
+		return "", fmt.Errorf("all requires a slice field, got %v", field.Type())
+	}
+
+	slice := reflect.MakeSlice(field.Type(), 0, len(indices))
+	for _, index := range indices {
+		if index >= len(record) {
+			continue
+		}
+		value := record[index]
+
+		elem := reflect.New(field.Type().Elem()).Elem()
+		if err := islyParseField(elem, value, tag); err != nil {
+			return value, fmt.Errorf("column %d: %w", index+1, err)
+		}
+		if err := islyValidateField(elem, value, tag); err != nil {
+			return value, fmt.Errorf("column %d: %w", index+1, err)
+		}
+		slice = reflect.Append(slice, elem)
+	}
+
+	field.Set(slice)
+	return "", nil
+}
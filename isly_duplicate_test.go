@@ -0,0 +1,156 @@
+package isly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslyBuildHeader(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		header    []string
+		mode      DuplicateHeaderMode
+		expected  *islyHeader
+		expectErr string
+	}{
+		{
+			desc:   "no duplicates",
+			header: []string{"a", "b"},
+			expected: &islyHeader{
+				names:      []string{"a", "b"},
+				index:      map[string]int{"a": 0, "b": 1},
+				duplicates: map[string][]int{},
+			},
+		},
+		{
+			desc:   "last column wins by default",
+			header: []string{"a", "b", "a"},
+			expected: &islyHeader{
+				names:      []string{"a", "b", "a"},
+				index:      map[string]int{"a": 2, "b": 1},
+				duplicates: map[string][]int{"a": {0, 2}},
+			},
+		},
+		{
+			desc:      "error mode",
+			header:    []string{"a", "b", "a", "a"},
+			mode:      DuplicateHeaderError,
+			expectErr: "duplicate header 'a' in columns 1, 3, 4",
+		},
+		{
+			desc:   "rename mode skips taken names",
+			header: []string{"a", "a_2", "a", "a"},
+			mode:   DuplicateHeaderRename,
+			expected: &islyHeader{
+				names:      []string{"a", "a_2", "a_3", "a_4"},
+				index:      map[string]int{"a": 0, "a_2": 1, "a_3": 2, "a_4": 3},
+				duplicates: map[string][]int{"a": {0, 2, 3}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			built, err := islyBuildHeader(tc.header, tc.mode)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, built)
+			}
+		})
+	}
+}
+
+func TestUnmarshalCSVDuplicateHeaders(t *testing.T) {
+	const input = "name,phone,email,phone\nJohn,111,john@example.com,222\nJane,333,jane@example.com,\n"
+
+	t.Run("last column wins", func(t *testing.T) {
+		type Contact struct {
+			Name  string `isly:"name"`
+			Phone string `isly:"phone"`
+		}
+
+		var contacts []Contact
+		assert.NoError(t, NewDecoder().NewReader(strings.NewReader(input)).UnmarshalCSV(&contacts))
+		assert.Equal(t, []Contact{{"John", "222"}, {"Jane", ""}}, contacts)
+	})
+
+	t.Run("error mode", func(t *testing.T) {
+		var contacts []struct {
+			Name string `isly:"name"`
+		}
+
+		err := NewDecoder(DuplicateHeaders(DuplicateHeaderError)).NewReader(strings.NewReader(input)).UnmarshalCSV(&contacts)
+		assert.EqualError(t, err, "duplicate header 'phone' in columns 2, 4")
+	})
+
+	t.Run("rename mode", func(t *testing.T) {
+		type Contact struct {
+			Name   string `isly:"name"`
+			Phone  string `isly:"phone"`
+			Phone2 string `isly:"phone_2"`
+		}
+
+		var contacts []Contact
+		err := NewDecoder(DuplicateHeaders(DuplicateHeaderRename)).NewReader(strings.NewReader(input)).UnmarshalCSV(&contacts)
+		assert.NoError(t, err)
+		assert.Equal(t, []Contact{{"John", "111", "222"}, {"Jane", "333", ""}}, contacts)
+	})
+
+	t.Run("collect all columns", func(t *testing.T) {
+		type Contact struct {
+			Name   string   `isly:"name"`
+			Phones []string `isly:"phone, all"`
+			Emails []string `isly:"email, all"`
+		}
+
+		var contacts []Contact
+		assert.NoError(t, NewDecoder().NewReader(strings.NewReader(input)).UnmarshalCSV(&contacts))
+		assert.Equal(t, []Contact{
+			{"John", []string{"111", "222"}, []string{"john@example.com"}},
+			{"Jane", []string{"333", ""}, []string{"jane@example.com"}},
+		}, contacts)
+	})
+
+	t.Run("renamed columns collected by all are not extra", func(t *testing.T) {
+		type Row struct {
+			A     []string          `isly:"a, all"`
+			Extra map[string]string `isly:",extra"`
+		}
+
+		var rows []Row
+		err := NewDecoder(DuplicateHeaders(DuplicateHeaderRename)).NewReader(strings.NewReader("a,a,a,z\n1,2,3,q\n")).UnmarshalCSV(&rows)
+		assert.NoError(t, err)
+		assert.Equal(t, []Row{{[]string{"1", "2", "3"}, map[string]string{"z": "q"}}}, rows)
+	})
+
+	t.Run("collected columns are converted and validated", func(t *testing.T) {
+		type Score struct {
+			Scores []int `isly:"score, all, max=100"`
+		}
+
+		var scores []Score
+		err := NewDecoder().NewReader(strings.NewReader("score,score\n90,80\n")).UnmarshalCSV(&scores)
+		assert.NoError(t, err)
+		assert.Equal(t, []Score{{[]int{90, 80}}}, scores)
+
+		err = NewDecoder().NewReader(strings.NewReader("score,score\n90,180\n")).UnmarshalCSV(&scores)
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.ErrorContains(t, err, "row 1, column 'score': column 2:")
+
+		err = NewDecoder().NewReader(strings.NewReader("score,score\n90,x\n")).UnmarshalCSV(&scores)
+		assert.Error(t, err)
+	})
+
+	t.Run("all requires a slice", func(t *testing.T) {
+		var rows []struct {
+			Phone string `isly:"phone, all"`
+		}
+
+		assert.Error(t, NewDecoder().NewReader(strings.NewReader(input)).UnmarshalCSV(&rows))
+	})
+}
@@ -0,0 +1,152 @@
+package isly
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	// Units accepted by the `unit=` tag option
+	durationUnits = map[string]time.Duration{
+		"ns": time.Nanosecond,
+		"us": time.Microsecond,
+		"µs": time.Microsecond,
+		"ms": time.Millisecond,
+		"s":  time.Second,
+		"m":  time.Minute,
+		"h":  time.Hour,
+		"d":  24 * time.Hour,
+	}
+)
+
+// islyParseDuration accepts Go duration strings ("1h30m"), ISO-8601 durations ("PT1H30M")
+// and plain numbers, which are multiplied by unit (nanoseconds when unit is empty).
+func islyParseDuration(value string, unit string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	multiplier := time.Nanosecond
+	if unit != "" {
+		m, ok := durationUnits[strings.ToLower(unit)]
+		if !ok {
+			return 0, fmt.Errorf("unknown duration unit '%s'", unit)
+		}
+		multiplier = m
+	}
+
+	// plain number
+	if intVal, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if intVal != 0 && (intVal > math.MaxInt64/int64(multiplier) || intVal < math.MinInt64/int64(multiplier)) {
+			return 0, fmt.Errorf("duration '%s' overflows", value)
+		}
+		return time.Duration(intVal) * multiplier, nil
+	}
+	if floatVal, err := strconv.ParseFloat(value, 64); err == nil && !math.IsNaN(floatVal) {
+		result := math.Round(floatVal * float64(multiplier))
+		if result >= math.MaxInt64 || result < math.MinInt64 {
+			return 0, fmt.Errorf("duration '%s' overflows", value)
+		}
+		return time.Duration(result), nil
+	}
+
+	// ISO-8601
+	upper := strings.ToUpper(value)
+	if strings.HasPrefix(upper, "P") || strings.HasPrefix(upper, "-P") || strings.HasPrefix(upper, "+P") {
+		return islyParseISODuration(upper)
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse duration '%s': %w", value, err)
+	}
+	return d, nil
+}
+
+// islyParseISODuration parses the PnWnDTnHnMnS subset of ISO-8601.
+// Years and months have no fixed length and are rejected.
+func islyParseISODuration(value string) (time.Duration, error) {
+	original := value
+
+	sign := 1.0
+	switch value[0] {
+	case '-':
+		sign = -1
+		value = value[1:]
+	case '+':
+		value = value[1:]
+	}
+	value = strings.TrimPrefix(value, "P")
+
+	if value == "" || value == "T" {
+		return 0, fmt.Errorf("failed to parse ISO-8601 duration '%s': empty duration", original)
+	}
+
+	var total float64
+	inTime := false
+	number := ""
+
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == ',':
+			if r == ',' {
+				r = '.'
+			}
+			number += string(r)
+			continue
+		case r == 'T':
+			if inTime || number != "" {
+				return 0, fmt.Errorf("failed to parse ISO-8601 duration '%s': unexpected 'T'", original)
+			}
+			inTime = true
+			continue
+		}
+
+		if number == "" {
+			return 0, fmt.Errorf("failed to parse ISO-8601 duration '%s': missing value before '%c'", original, r)
+		}
+		n, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse ISO-8601 duration '%s': %w", original, err)
+		}
+		number = ""
+
+		var unit time.Duration
+		switch {
+		case !inTime && r == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && r == 'D':
+			unit = 24 * time.Hour
+		case inTime && r == 'H':
+			unit = time.Hour
+		case inTime && r == 'M':
+			unit = time.Minute
+		case inTime && r == 'S':
+			unit = time.Second
+		case !inTime && (r == 'Y' || r == 'M'):
+			return 0, fmt.Errorf("failed to parse ISO-8601 duration '%s': years and months are not supported", original)
+		default:
+			return 0, fmt.Errorf("failed to parse ISO-8601 duration '%s': unexpected '%c'", original, r)
+		}
+
+		total += n * float64(unit)
+	}
+
+	if number != "" {
+		return 0, fmt.Errorf("failed to parse ISO-8601 duration '%s': missing unit designator", original)
+	}
+
+	total = math.Round(sign * total)
+	if total >= math.MaxInt64 || total < math.MinInt64 {
+		return 0, fmt.Errorf("duration '%s' overflows", original)
+	}
+
+	return time.Duration(total), nil
+}
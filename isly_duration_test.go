@@ -0,0 +1,154 @@
+package isly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslyParseDuration(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		unit      string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{
+			desc:     "go duration string",
+			input:    "1h30m",
+			expected: 90 * time.Minute,
+		},
+		{
+			desc:     "go duration with fraction",
+			input:    "1.5s",
+			expected: 1500 * time.Millisecond,
+		},
+		{
+			desc:     "negative go duration",
+			input:    "-2m",
+			expected: -2 * time.Minute,
+		},
+		{
+			desc:     "empty string",
+			input:    "   ",
+			expected: 0,
+		},
+		{
+			desc:     "plain number without unit is nanoseconds",
+			input:    "1500",
+			expected: 1500 * time.Nanosecond,
+		},
+		{
+			desc:     "plain number with ms unit",
+			input:    "250",
+			unit:     "ms",
+			expected: 250 * time.Millisecond,
+		},
+		{
+			desc:     "plain number with seconds unit",
+			input:    "  90 ",
+			unit:     "s",
+			expected: 90 * time.Second,
+		},
+		{
+			desc:     "fractional number with unit",
+			input:    "1.5",
+			unit:     "h",
+			expected: 90 * time.Minute,
+		},
+		{
+			desc:     "plain number with day unit",
+			input:    "2",
+			unit:     "d",
+			expected: 48 * time.Hour,
+		},
+		{
+			desc:     "go duration string ignores unit",
+			input:    "10s",
+			unit:     "ms",
+			expected: 10 * time.Second,
+		},
+		{
+			desc:     "ISO-8601 hours and minutes",
+			input:    "PT1H30M",
+			expected: 90 * time.Minute,
+		},
+		{
+			desc:     "ISO-8601 days and time",
+			input:    "P1DT2H",
+			expected: 26 * time.Hour,
+		},
+		{
+			desc:     "ISO-8601 weeks",
+			input:    "P2W",
+			expected: 14 * 24 * time.Hour,
+		},
+		{
+			desc:     "ISO-8601 fractional seconds",
+			input:    "PT0.5S",
+			expected: 500 * time.Millisecond,
+		},
+		{
+			desc:     "ISO-8601 lowercase",
+			input:    "pt45m",
+			expected: 45 * time.Minute,
+		},
+		{
+			desc:     "ISO-8601 negative",
+			input:    "-PT10S",
+			expected: -10 * time.Second,
+		},
+		{
+			desc:      "ISO-8601 years are ambiguous",
+			input:     "P1Y",
+			expectErr: true,
+		},
+		{
+			desc:      "ISO-8601 months are ambiguous",
+			input:     "P2M",
+			expectErr: true,
+		},
+		{
+			desc:      "ISO-8601 missing designator",
+			input:     "PT15",
+			expectErr: true,
+		},
+		{
+			desc:      "ISO-8601 empty",
+			input:     "PT",
+			expectErr: true,
+		},
+		{
+			desc:      "unknown unit",
+			input:     "5",
+			unit:      "fortnight",
+			expectErr: true,
+		},
+		{
+			desc:      "overflow",
+			input:     "9223372036854775807",
+			unit:      "h",
+			expectErr: true,
+		},
+		{
+			desc:      "invalid input",
+			input:     "soon",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := islyParseDuration(tc.input, tc.unit)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package isly
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding names the character encoding of the CSV input.
+type Encoding string
+
+const (
+	EncodingUTF8        Encoding = "utf-8"
+	EncodingUTF16LE     Encoding = "utf-16le"
+	EncodingUTF16BE     Encoding = "utf-16be"
+	EncodingLatin1      Encoding = "iso-8859-1"
+	EncodingWindows1252 Encoding = "windows-1252"
+)
+
+// Other names accepted by WithEncoding
+var encodingAliases = map[string]Encoding{
+	"utf8":        EncodingUTF8,
+	"latin1":      EncodingLatin1,
+	"latin-1":     EncodingLatin1,
+	"iso8859-1":   EncodingLatin1,
+	"cp1252":      EncodingWindows1252,
+	"windows1252": EncodingWindows1252,
+	"utf16le":     EncodingUTF16LE,
+	"utf16be":     EncodingUTF16BE,
+	"utf-16-le":   EncodingUTF16LE,
+	"utf-16-be":   EncodingUTF16BE,
+}
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// windows1252Table maps bytes 0x80-0x9F of Windows-1252, the only range where it differs
+// from Latin-1. The five unassigned bytes keep their Latin-1 (C1 control) meaning.
+var windows1252Table = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// islyParseEncoding returns the Encoding for name, case-insensitive and with common
+// aliases. An empty name means detection from the input.
+func islyParseEncoding(name string) (Encoding, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	switch Encoding(normalized) {
+	case "", EncodingUTF8, EncodingUTF16LE, EncodingUTF16BE, EncodingLatin1, EncodingWindows1252:
+		return Encoding(normalized), nil
+	}
+	if alias, ok := encodingAliases[normalized]; ok {
+		return alias, nil
+	}
+	return "", fmt.Errorf("unsupported encoding '%s'", name)
+}
+
+// islyDecodeText returns a UTF-8 reader of source. A byte order mark always wins and is
+// removed. Without one, encoding is used, and when that is empty too, UTF-16 is
+// recognized by the zero bytes of the ASCII header; anything else is read as UTF-8.
+func islyDecodeText(source io.Reader, encoding Encoding) (io.Reader, error) {
+	encoding, err := islyParseEncoding(string(encoding))
+	if err != nil {
+		return nil, err
+	}
+
+	src := bufio.NewReader(source)
+	start, _ := src.Peek(4)
+
+	switch {
+	case bytes.HasPrefix(start, utf8BOM):
+		src.Discard(len(utf8BOM))
+		return src, nil
+	case bytes.HasPrefix(start, utf16LEBOM):
+		src.Discard(len(utf16LEBOM))
+		encoding = EncodingUTF16LE
+	case bytes.HasPrefix(start, utf16BEBOM):
+		src.Discard(len(utf16BEBOM))
+		encoding = EncodingUTF16BE
+	case encoding == "" && len(start) == 4:
+		if start[0] != 0 && start[1] == 0 && start[2] != 0 && start[3] == 0 {
+			encoding = EncodingUTF16LE
+		} else if start[0] == 0 && start[1] != 0 && start[2] == 0 && start[3] != 0 {
+			encoding = EncodingUTF16BE
+		}
+	}
+
+	switch encoding {
+	case EncodingUTF16LE:
+		return &islyTextReader{decoder: &islyUTF16Decoder{src: src}}, nil
+	case EncodingUTF16BE:
+		return &islyTextReader{decoder: &islyUTF16Decoder{src: src, bigEndian: true}}, nil
+	case EncodingLatin1:
+		return &islyTextReader{decoder: &islySingleByteDecoder{src: src}}, nil
+	case EncodingWindows1252:
+		return &islyTextReader{decoder: &islySingleByteDecoder{src: src, table: &windows1252Table}}, nil
+	}
+
+	return src, nil
+}
+
+// islyRuneDecoder reads one character of the input at a time.
+type islyRuneDecoder interface {
+	next() (rune, error)
+}
+
+// islyTextReader re-encodes the characters of its decoder as UTF-8.
+type islyTextReader struct {
+	decoder islyRuneDecoder
+	pending []byte
+	err     error
+}
+
+func (r *islyTextReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.pending) > 0 {
+			copied := copy(p[n:], r.pending)
+			r.pending = r.pending[copied:]
+			n += copied
+			continue
+		}
+		if r.err != nil {
+			break
+		}
+
+		c, err := r.decoder.next()
+		if err != nil {
+			r.err = err
+			break
+		}
+		r.pending = utf8.AppendRune(r.pending[:0], c)
+	}
+
+	if n == 0 && r.err != nil {
+		return 0, r.err
+	}
+	return n, nil
+}
+
+// islySingleByteDecoder decodes Latin-1, or Windows-1252 when table is set.
+type islySingleByteDecoder struct {
+	src   *bufio.Reader
+	table *[32]rune
+}
+
+func (d *islySingleByteDecoder) next() (rune, error) {
+	b, err := d.src.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if d.table != nil && b >= 0x80 && b <= 0x9F {
+		return d.table[b-0x80], nil
+	}
+	return rune(b), nil
+}
+
+// islyUTF16Decoder decodes UTF-16, replacing unpaired surrogates and a trailing odd
+// byte with U+FFFD.
+type islyUTF16Decoder struct {
+	src       *bufio.Reader
+	bigEndian bool
+
+	// a code unit read ahead that did not complete a surrogate pair
+	held    uint16
+	hasHeld bool
+}
+
+func (d *islyUTF16Decoder) unit() (uint16, error) {
+	if d.hasHeld {
+		d.hasHeld = false
+		return d.held, nil
+	}
+
+	var pair [2]byte
+	if _, err := io.ReadFull(d.src, pair[:]); err != nil {
+		return 0, err
+	}
+	if d.bigEndian {
+		return uint16(pair[0])<<8 | uint16(pair[1]), nil
+	}
+	return uint16(pair[1])<<8 | uint16(pair[0]), nil
+}
+
+func (d *islyUTF16Decoder) next() (rune, error) {
+	first, err := d.unit()
+	if err == io.ErrUnexpectedEOF {
+		return utf8.RuneError, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if !utf16.IsSurrogate(rune(first)) {
+		return rune(first), nil
+	}
+
+	second, err := d.unit()
+	if err != nil {
+		// the input ended inside a surrogate pair
+		return utf8.RuneError, nil
+	}
+
+	if c := utf16.DecodeRune(rune(first), rune(second)); c != utf8.RuneError {
+		return c, nil
+	}
+
+	// not a pair, the second unit starts the next character
+	d.held, d.hasHeld = second, true
+	return utf8.RuneError, nil
+}
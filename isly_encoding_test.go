@@ -0,0 +1,124 @@
+package isly
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// utf16Bytes encodes s as UTF-16 in the given byte order, prefixed by bom.
+func utf16Bytes(s string, bigEndian bool, bom bool) []byte {
+	var buf bytes.Buffer
+	units := utf16.Encode([]rune(s))
+	if bom {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+	for _, u := range units {
+		if bigEndian {
+			buf.WriteByte(byte(u >> 8))
+			buf.WriteByte(byte(u))
+		} else {
+			buf.WriteByte(byte(u))
+			buf.WriteByte(byte(u >> 8))
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestIslyDecodeText(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     []byte
+		encoding  Encoding
+		expected  string
+		expectErr bool
+	}{
+		{desc: "plain utf-8", input: []byte("name\nJosé\n"), expected: "name\nJosé\n"},
+		{desc: "utf-8 bom is stripped", input: append([]byte{0xEF, 0xBB, 0xBF}, "name\nJosé\n"...), expected: "name\nJosé\n"},
+		{desc: "utf-16le with bom", input: utf16Bytes("name\nJosé 😀\n", false, true), expected: "name\nJosé 😀\n"},
+		{desc: "utf-16be with bom", input: utf16Bytes("name\nJosé\n", true, true), expected: "name\nJosé\n"},
+		{desc: "utf-16le detected without bom", input: utf16Bytes("name\nJosé\n", false, false), expected: "name\nJosé\n"},
+		{desc: "utf-16be by option", input: utf16Bytes("né", true, false), encoding: EncodingUTF16BE, expected: "né"},
+		{desc: "utf-16 odd trailing byte", input: append(utf16Bytes("ab", false, true), 'c'), expected: "ab�"},
+		{desc: "utf-16 unpaired surrogate", input: []byte{0xFF, 0xFE, 0x3D, 0xD8, 0x61, 0x00}, expected: "�a"},
+		{desc: "latin-1", input: []byte("Jos\xe9,\x80\n"), encoding: EncodingLatin1, expected: "José,\u0080\n"},
+		{desc: "latin-1 alias", input: []byte("Jos\xe9"), encoding: "Latin1", expected: "José"},
+		{desc: "windows-1252", input: []byte("\x80 5,\x93quoted\x94,Jos\xe9,\x81"), encoding: EncodingWindows1252, expected: "€ 5,“quoted”,José,\u0081"},
+		{desc: "windows-1252 alias", input: []byte("\x99"), encoding: "cp1252", expected: "™"},
+		{desc: "bom wins over the option", input: append([]byte{0xEF, 0xBB, 0xBF}, "é"...), encoding: EncodingWindows1252, expected: "é"},
+		{desc: "unknown encoding", input: []byte("a"), encoding: "ebcdic", expectErr: true},
+		{desc: "empty input", input: []byte{}, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			reader, err := islyDecodeText(bytes.NewReader(tc.input), tc.encoding)
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+				return
+			}
+			assert.NoError(t, err, "expected no error but got: %v", err)
+
+			text, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(text))
+		})
+	}
+}
+
+func TestIslyTextReaderSmallBuffer(t *testing.T) {
+	reader, err := islyDecodeText(bytes.NewReader(utf16Bytes("€😀é", false, true)), "")
+	assert.NoError(t, err)
+
+	// characters longer than the buffer are split over several reads
+	var out strings.Builder
+	buf := make([]byte, 1)
+	for {
+		n, err := reader.Read(buf)
+		out.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "€😀é", out.String())
+}
+
+func TestUnmarshalCSVEncoding(t *testing.T) {
+	type Person struct {
+		Name string `isly:"name"`
+		City string `isly:"city"`
+	}
+
+	t.Run("excel utf-16 export", func(t *testing.T) {
+		component := NewIsly()
+		assert.NoError(t, component.ReadFile(writeTestFile(t, "people.csv", utf16Bytes("name,city\nJosé,Zürich\n", false, true))))
+
+		var people []Person
+		assert.NoError(t, component.UnmarshalCSV(&people))
+		assert.Equal(t, []Person{{"José", "Zürich"}}, people)
+	})
+
+	t.Run("bom does not end up in the first header", func(t *testing.T) {
+		component := NewIsly()
+		assert.NoError(t, component.ReadFile(writeTestFile(t, "people.csv", []byte("\xEF\xBB\xBFname,city\nJohn,Paris\n"))))
+
+		var people []Person
+		assert.NoError(t, component.UnmarshalCSV(&people))
+		assert.Equal(t, []Person{{"John", "Paris"}}, people)
+	})
+
+	t.Run("windows-1252 stream", func(t *testing.T) {
+		var people []Person
+		err := NewDecoder(WithEncoding(EncodingWindows1252)).
+			NewReader(strings.NewReader("name,city\n\x93Jos\xe9\x94,K\xf6ln\n")).
+			UnmarshalCSV(&people)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Person{{"“José”", "Köln"}}, people)
+	})
+}
@@ -0,0 +1,75 @@
+package isly
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type islyEnumEntry struct {
+	name  string
+	value string
+}
+
+// islyParseEnumSpec parses "active|inactive|pending" or "active:1|inactive:2".
+func islyParseEnumSpec(spec string) ([]islyEnumEntry, error) {
+	var entries []islyEnumEntry
+
+	for _, part := range strings.Split(spec, "|") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(part, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok {
+			value = name
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid enum entry '%s'", part)
+		}
+
+		entries = append(entries, islyEnumEntry{name: name, value: value})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("enum has no values")
+	}
+
+	return entries, nil
+}
+
+// islyParseEnum checks value against the `enum=` option and stores the matching entry,
+// or the number it maps to, in field. With the `ignorecase` flag "ACTIVE" matches "active"
+// and the spelling from the tag is stored. Empty values leave the field at its zero value.
+func islyParseEnum(field reflect.Value, value string, tag islyTag) error {
+	entries, err := islyParseEnumSpec(tag.option("enum"))
+	if err != nil {
+		return err
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	ignoreCase := tag.hasOption("ignorecase")
+
+	for _, entry := range entries {
+		if entry.name == value || (ignoreCase && strings.EqualFold(entry.name, value)) {
+			if err := islyParsePrimitiveData(field, entry.value, field.Type(), ""); err != nil {
+				return fmt.Errorf("enum value '%s': %w", entry.name, err)
+			}
+			return nil
+		}
+	}
+
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.name
+	}
+
+	return fmt.Errorf("value '%s' is not one of %s", value, strings.Join(names, "|"))
+}
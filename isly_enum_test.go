@@ -0,0 +1,122 @@
+package isly
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslyParseEnum(t *testing.T) {
+	type Status int
+	type Level string
+
+	testCases := []struct {
+		desc      string
+		fieldType reflect.Type
+		value     string
+		tag       string
+		expected  interface{}
+		expectErr bool
+	}{
+		{
+			desc:      "allowed value",
+			fieldType: reflect.TypeOf(""),
+			value:     "active",
+			tag:       "status, enum=active|inactive|pending",
+			expected:  "active",
+		},
+		{
+			desc:      "allowed value with whitespace",
+			fieldType: reflect.TypeOf(""),
+			value:     "  pending ",
+			tag:       "status, enum=active|inactive|pending",
+			expected:  "pending",
+		},
+		{
+			desc:      "unexpected value",
+			fieldType: reflect.TypeOf(""),
+			value:     "deleted",
+			tag:       "status, enum=active|inactive|pending",
+			expectErr: true,
+		},
+		{
+			desc:      "case sensitive by default",
+			fieldType: reflect.TypeOf(""),
+			value:     "Active",
+			tag:       "status, enum=active|inactive",
+			expectErr: true,
+		},
+		{
+			desc:      "ignorecase stores the tag spelling",
+			fieldType: reflect.TypeOf(Level("")),
+			value:     "ACTIVE",
+			tag:       "status, enum=active|inactive, ignorecase",
+			expected:  Level("active"),
+		},
+		{
+			desc:      "empty value is zero",
+			fieldType: reflect.TypeOf(""),
+			value:     "",
+			tag:       "status, enum=active|inactive",
+			expected:  "",
+		},
+		{
+			desc:      "mapped to integer-backed enum",
+			fieldType: reflect.TypeOf(Status(0)),
+			value:     "inactive",
+			tag:       "status, enum=active:1|inactive:2",
+			expected:  Status(2),
+		},
+		{
+			desc:      "mapped with ignorecase",
+			fieldType: reflect.TypeOf(Status(0)),
+			value:     "Active",
+			tag:       "status, enum=active:1|inactive:2, ignorecase",
+			expected:  Status(1),
+		},
+		{
+			desc:      "mapped rejects the number itself",
+			fieldType: reflect.TypeOf(Status(0)),
+			value:     "1",
+			tag:       "status, enum=active:1|inactive:2",
+			expectErr: true,
+		},
+		{
+			desc:      "mapped value does not fit the field",
+			fieldType: reflect.TypeOf(Status(0)),
+			value:     "active",
+			tag:       "status, enum=active:one",
+			expectErr: true,
+		},
+		{
+			desc:      "numeric enum without mapping",
+			fieldType: reflect.TypeOf(0),
+			value:     "3",
+			tag:       "level, enum=1|2|3",
+			expected:  3,
+		},
+		{
+			desc:      "empty enum",
+			fieldType: reflect.TypeOf(""),
+			value:     "a",
+			tag:       "status, enum=",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fieldValue := reflect.New(tc.fieldType).Elem()
+
+			err := islyParseEnum(fieldValue, tc.value, islyParseTag(tc.tag))
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, fieldValue.Interface())
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package isly
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoFile is returned by UnmarshalCSV when no file is open, because ReadFile was never
+// called, failed, or the file was already decoded or closed.
+var ErrNoFile = errors.New("no file provided")
+
+// ErrValidation is wrapped by every error coming from a validation rule
+// (min=, max=, len=, regex=, oneof=, notempty, email, url).
+var ErrValidation = errors.New("validation failed")
+
+// FieldError describes a CSV cell that could not be parsed or failed validation.
+// Use errors.As to get it from the error returned by UnmarshalCSV.
+type FieldError struct {
+	Row    int    // data row, starting at 1 (0 if unknown)
+	Column string // CSV header the field is bound to
+	Value  string // raw cell value
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+	if e.Row > 0 {
+		return fmt.Sprintf("row %d, column '%s': %v", e.Row, e.Column, e.Err)
+	}
+	return fmt.Sprintf("column '%s': %v", e.Column, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// islyRowError attaches the data row number to an error from processStructFromRecord.
+func islyRowError(err error, row int) error {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		fieldErr.Row = row
+		return fieldErr
+	}
+	return fmt.Errorf("error processing row %d: %w", row, err)
+}
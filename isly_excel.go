@@ -0,0 +1,80 @@
+package isly
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Largest serial Excel accepts (9999-12-31)
+	excelMaxSerial = 2958465
+)
+
+var (
+	// Day 1 of the 1900 date system is 1900-01-01. Excel keeps Lotus 1-2-3's
+	// phantom 1900-02-29 (serial 60), so serials after it are one day ahead.
+	excelEpoch1900      = time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)
+	excelEpoch1900Shift = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+	// Day 0 of the 1904 date system (old Excel for Mac) is 1904-01-01
+	excelEpoch1904 = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func islyIsExcelSerial(value string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return err == nil
+}
+
+// islyParseExcelDate converts an Excel serial date number (45123 or 45123.5) into time.Time.
+// epoch is "1900" (default) or "1904".
+func islyParseExcelDate(value string, epoch string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse excel serial date '%s': %w", value, err)
+	}
+
+	if math.IsNaN(serial) || serial < 0 || serial > excelMaxSerial {
+		return time.Time{}, fmt.Errorf("excel serial date '%s' out of range", value)
+	}
+
+	var base time.Time
+	switch epoch {
+	case "", "1900":
+		base = excelEpoch1900
+		if serial >= 61 {
+			base = excelEpoch1900Shift
+		}
+	case "1904":
+		base = excelEpoch1904
+	default:
+		return time.Time{}, fmt.Errorf("unknown excel epoch '%s'", epoch)
+	}
+
+	days, fraction := math.Modf(serial)
+
+	// time of day, rounded to the millisecond Excel stores
+	millis := math.Round(fraction * float64(24*time.Hour/time.Millisecond))
+
+	return base.AddDate(0, 0, int(days)).Add(time.Duration(millis) * time.Millisecond), nil
+}
+
+// islyParseExcelField sets a time.Time field from an Excel serial date, or from the usual
+// date formats when value is not a serial number.
+func islyParseExcelField(field reflect.Value, value string, epoch string) error {
+	if field.Type() != timeType || !islyIsExcelSerial(value) {
+		return islyParsePrimitiveData(field, value, field.Type(), "")
+	}
+
+	timeVal, err := islyParseExcelDate(value, epoch)
+	if err != nil {
+		return err
+	}
+	field.Set(reflect.ValueOf(timeVal))
+	return nil
+}
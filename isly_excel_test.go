@@ -0,0 +1,106 @@
+package isly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslyParseExcelDate(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		epoch     string
+		expected  time.Time
+		expectErr bool
+	}{
+		{
+			desc:     "1900 epoch - whole day",
+			input:    "45123",
+			expected: time.Date(2023, 7, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "1900 epoch - explicit",
+			input:    "45123",
+			epoch:    "1900",
+			expected: time.Date(2023, 7, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "1900 epoch - half day",
+			input:    "45123.5",
+			expected: time.Date(2023, 7, 16, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "1900 epoch - time of day",
+			input:    "45123.75",
+			expected: time.Date(2023, 7, 16, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "1900 epoch - first day",
+			input:    "1",
+			expected: time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "1900 epoch - before phantom leap day",
+			input:    "59",
+			expected: time.Date(1900, 2, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "1900 epoch - after phantom leap day",
+			input:    "61",
+			expected: time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "1904 epoch - day zero",
+			input:    "0",
+			epoch:    "1904",
+			expected: time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "1904 epoch - same date as 1900 serial 45123",
+			input:    "43661",
+			epoch:    "1904",
+			expected: time.Date(2023, 7, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "with whitespace",
+			input:    "  45123  ",
+			expected: time.Date(2023, 7, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:      "negative serial",
+			input:     "-1",
+			expectErr: true,
+		},
+		{
+			desc:      "serial past 9999-12-31",
+			input:     "2958466",
+			expectErr: true,
+		},
+		{
+			desc:      "unknown epoch",
+			input:     "45123",
+			epoch:     "2000",
+			expectErr: true,
+		},
+		{
+			desc:      "not a number",
+			input:     "2023-07-16",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := islyParseExcelDate(tc.input, tc.epoch)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
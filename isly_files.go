@@ -0,0 +1,129 @@
+package isly
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadFiles queues csvFiles to be decoded by the next UnmarshalCSV call as one dataset,
+// in the given order. Every file must have the same columns as the first one, in any
+// order. Files are opened one at a time while decoding.
+func (i *newIslyComponent) ReadFiles(csvFiles ...string) error {
+	if err := i.Close(); err != nil {
+		return err
+	}
+
+	if len(csvFiles) == 0 {
+		return fmt.Errorf("no files to read")
+	}
+
+	// fail early on a missing file instead of halfway through the decode
+	for _, csvFile := range csvFiles {
+		info, err := os.Stat(csvFile)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory", csvFile)
+		}
+	}
+
+	i.files = append([]string(nil), csvFiles...)
+	return nil
+}
+
+// ReadGlob queues the files matching pattern (see filepath.Match), in lexical order,
+// like ReadFiles.
+func (i *newIslyComponent) ReadGlob(pattern string) error {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no files match '%s'", pattern)
+	}
+
+	return i.ReadFiles(matches...)
+}
+
+// islyFileSources returns a source per file that opens and decompresses it on demand.
+func islyFileSources(csvFiles []string, zipEntry string) []islySource {
+	sources := make([]islySource, len(csvFiles))
+	for j, csvFile := range csvFiles {
+		sources[j] = islySource{
+			name: csvFile,
+			open: func() (io.Reader, io.Closer, *islyByteCounter, error) {
+				file, err := os.Open(csvFile)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+
+				sourceRead := islyCountFile(file)
+				source, sourceCloser, err := islyDecompress(sourceRead, csvFile, zipEntry)
+				if err != nil {
+					file.Close()
+					return nil, nil, nil, err
+				}
+
+				return source, islyMultiCloser{sourceCloser, file}, sourceRead, nil
+			},
+		}
+	}
+	return sources
+}
+
+// islyMultiCloser closes its closers in order and returns the first error.
+type islyMultiCloser []io.Closer
+
+func (c islyMultiCloser) Close() error {
+	var first error
+	for _, closer := range c {
+		if closer == nil {
+			continue
+		}
+		if err := closer.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// islyCompareHeaders checks that header has the same columns as expected, in any order.
+func islyCompareHeaders(expected []string, header []string) error {
+	seen := make(map[string]bool, len(header))
+	for _, name := range header {
+		seen[name] = true
+	}
+
+	var missing []string
+	for _, name := range expected {
+		if !seen[name] {
+			missing = append(missing, name)
+		}
+		delete(seen, name)
+	}
+
+	var unexpected []string
+	for _, name := range header {
+		if seen[name] {
+			unexpected = append(unexpected, name)
+			delete(seen, name)
+		}
+	}
+
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return nil
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing %s", strings.Join(missing, ", ")))
+	}
+	if len(unexpected) > 0 {
+		problems = append(problems, fmt.Sprintf("unexpected %s", strings.Join(unexpected, ", ")))
+	}
+	return fmt.Errorf("header does not match the first file: %s", strings.Join(problems, "; "))
+}
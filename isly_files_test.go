@@ -0,0 +1,116 @@
+package isly
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFiles(t *testing.T) {
+	type Sale struct {
+		Day    string `isly:"day"`
+		Amount int    `isly:"amount"`
+		From   Source `isly:",source"`
+		File   string `isly:",source"`
+	}
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "data_2024-01-01.csv")
+	second := filepath.Join(dir, "data_2024-01-02.csv.gz")
+	writeFileAt(t, first, []byte("day,amount\nmon,1\n\"tue\",2\n"))
+	writeFileAt(t, second, gzipTestData(t, "amount,day\n3,\"wed\nnight\"\n4,thu\n"))
+
+	t.Run("files decode into one slice", func(t *testing.T) {
+		var rows []int
+		component := NewIsly(WithProgress(func(n int, bytesRead int64) { rows = append(rows, n) }))
+		assert.NoError(t, component.ReadFiles(first, second))
+
+		var sales []Sale
+		assert.NoError(t, component.UnmarshalCSV(&sales))
+		assert.Equal(t, []Sale{
+			{"mon", 1, Source{first, 2}, first},
+			{"tue", 2, Source{first, 3}, first},
+			{"wed\nnight", 3, Source{second, 2}, second},
+			{"thu", 4, Source{second, 4}, second},
+		}, sales)
+		assert.Equal(t, []int{1, 2, 3, 4}, rows)
+	})
+
+	t.Run("glob in lexical order", func(t *testing.T) {
+		component := NewIsly()
+		assert.NoError(t, component.ReadGlob(filepath.Join(dir, "data_2024-01-*")))
+
+		var sales []Sale
+		assert.NoError(t, component.UnmarshalCSV(&sales))
+		if assert.Len(t, sales, 4) {
+			assert.Equal(t, "mon", sales[0].Day)
+			assert.Equal(t, "thu", sales[3].Day)
+		}
+	})
+
+	t.Run("struct target takes the first row", func(t *testing.T) {
+		component := NewIsly()
+		assert.NoError(t, component.ReadFiles(second, first))
+
+		var sale Sale
+		assert.NoError(t, component.UnmarshalCSV(&sale))
+		assert.Equal(t, Sale{"wed\nnight", 3, Source{second, 2}, second}, sale)
+	})
+
+	t.Run("incompatible header", func(t *testing.T) {
+		other := filepath.Join(t.TempDir(), "other.csv")
+		writeFileAt(t, other, []byte("day,total,note\nfri,5,x\n"))
+
+		component := NewIsly()
+		assert.NoError(t, component.ReadFiles(first, other))
+
+		var sales []Sale
+		err := component.UnmarshalCSV(&sales)
+		assert.EqualError(t, err, other+": header does not match the first file: missing amount; unexpected total, note")
+	})
+
+	t.Run("errors name the file", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.csv")
+		writeFileAt(t, bad, []byte("day,amount\nfri,five\n"))
+
+		component := NewIsly()
+		assert.NoError(t, component.ReadFiles(first, bad))
+
+		var sales []Sale
+		err := component.UnmarshalCSV(&sales)
+		assert.ErrorContains(t, err, bad+": row 1, column 'amount'")
+	})
+
+	t.Run("missing file fails early", func(t *testing.T) {
+		component := NewIsly()
+		assert.Error(t, component.ReadFiles(first, filepath.Join(dir, "missing.csv")))
+
+		var sales []Sale
+		assert.ErrorIs(t, component.UnmarshalCSV(&sales), ErrNoFile)
+	})
+
+	t.Run("no glob match", func(t *testing.T) {
+		assert.Error(t, NewIsly().ReadGlob(filepath.Join(dir, "nothing_*.csv")))
+		assert.Error(t, NewIsly().ReadFiles())
+	})
+
+	t.Run("invalid source field type", func(t *testing.T) {
+		type Bad struct {
+			Day  string `isly:"day"`
+			From int    `isly:",source"`
+		}
+
+		component := NewIsly()
+		assert.NoError(t, component.ReadFile(first))
+
+		var rows []Bad
+		assert.Error(t, component.UnmarshalCSV(&rows))
+	})
+}
+
+func TestIslyCompareHeaders(t *testing.T) {
+	assert.NoError(t, islyCompareHeaders([]string{"a", "b"}, []string{"b", "a"}))
+	assert.EqualError(t, islyCompareHeaders([]string{"a", "b"}, []string{"a"}), "header does not match the first file: missing b")
+	assert.EqualError(t, islyCompareHeaders([]string{"a"}, []string{"a", "c"}), "header does not match the first file: unexpected c")
+}
@@ -0,0 +1,118 @@
+package isly
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// HeaderRow reads the header from line n of the file, starting at 1. Everything above
+// it, such as a report title, is skipped.
+func HeaderRow(n int) Option {
+	return func(o *islyOptions) {
+		o.headerRow = n
+	}
+}
+
+// StackedHeader reads a header spread over rows lines, like "Q1" above "Revenue" and
+// "Cost", and joins the lines of each column with "." into "Q1.Revenue" and "Q1.Cost".
+// An empty cell in an upper line continues the group on its left, as merged cells are
+// exported empty; use an explicit value to start a column without a group.
+func StackedHeader(rows int) Option {
+	return func(o *islyOptions) {
+		o.headerRows = rows
+	}
+}
+
+// readHeader skips the lines above the header and returns the header names.
+func (o islyOptions) readHeader(reader *csv.Reader) ([]string, error) {
+	// Skip lines above the header
+	for n := 0; n < o.skipRows; n++ {
+		if _, err := reader.Read(); err != nil {
+			return nil, fmt.Errorf("failed to skip CSV row %d: %w", n+1, err)
+		}
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	if o.headerRow > 0 {
+		for {
+			line, _ := reader.FieldPos(0)
+			if line == o.headerRow {
+				break
+			}
+			if line > o.headerRow {
+				return nil, fmt.Errorf("failed to read CSV header: no row starts on line %d", o.headerRow)
+			}
+
+			if header, err = reader.Read(); err != nil {
+				return nil, fmt.Errorf("failed to read CSV header on line %d: %w", o.headerRow, err)
+			}
+		}
+	}
+
+	if o.headerRows <= 1 {
+		return header, nil
+	}
+
+	stacked := [][]string{header}
+	for len(stacked) < o.headerRows {
+		record, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV header row %d: %w", len(stacked)+1, err)
+		}
+		stacked = append(stacked, record)
+	}
+
+	return islyStackHeaders(stacked), nil
+}
+
+// islyStackHeaders joins stacked header rows into one name per column. Empty cells of
+// every row but the last are filled from the left while the rows above match.
+func islyStackHeaders(rows [][]string) []string {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+
+	filled := make([][]string, len(rows))
+	for r, row := range rows {
+		filled[r] = make([]string, width)
+		for j := 0; j < width; j++ {
+			if j < len(row) {
+				filled[r][j] = strings.TrimSpace(row[j])
+			}
+
+			if r == len(rows)-1 || filled[r][j] != "" || j == 0 {
+				continue
+			}
+
+			// continue the group on the left only inside the same parent group
+			sameParent := true
+			for above := 0; above < r; above++ {
+				if filled[above][j] != filled[above][j-1] {
+					sameParent = false
+					break
+				}
+			}
+			if sameParent {
+				filled[r][j] = filled[r][j-1]
+			}
+		}
+	}
+
+	header := make([]string, width)
+	for j := 0; j < width; j++ {
+		var parts []string
+		for r := range filled {
+			if filled[r][j] != "" {
+				parts = append(parts, filled[r][j])
+			}
+		}
+		header[j] = strings.Join(parts, ".")
+	}
+	return header
+}
@@ -0,0 +1,117 @@
+package isly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslyStackHeaders(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rows     [][]string
+		expected []string
+	}{
+		{
+			desc:     "group above two columns",
+			rows:     [][]string{{"", "Q1", "", "Q2", ""}, {"Region", "Revenue", "Cost", "Revenue", "Cost"}},
+			expected: []string{"Region", "Q1.Revenue", "Q1.Cost", "Q2.Revenue", "Q2.Cost"},
+		},
+		{
+			desc:     "shorter group row",
+			rows:     [][]string{{"", "Q1"}, {"Region", "Revenue", "Cost"}},
+			expected: []string{"Region", "Q1.Revenue", "Q1.Cost"},
+		},
+		{
+			desc: "three levels fill inside their parent only",
+			rows: [][]string{
+				{"2024", "", "", "2025"},
+				{"Q1", "", "Q2", ""},
+				{"Rev", "Cost", "Rev", "Rev"},
+			},
+			expected: []string{"2024.Q1.Rev", "2024.Q1.Cost", "2024.Q2.Rev", "2025.Rev"},
+		},
+		{
+			desc:     "cells are trimmed",
+			rows:     [][]string{{" Q1 ", ""}, {" Revenue", "Cost "}},
+			expected: []string{"Q1.Revenue", "Q1.Cost"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, islyStackHeaders(tc.rows))
+		})
+	}
+}
+
+func TestUnmarshalCSVHeaderOptions(t *testing.T) {
+	type Quarter struct {
+		Region    string `isly:"Region"`
+		Q1Revenue int    `isly:"Q1.Revenue"`
+		Q1Cost    int    `isly:"Q1.Cost"`
+		Q2Revenue int    `isly:"Q2.Revenue"`
+		Line      int    `isly:",line"`
+	}
+
+	report := "Quarterly report\n\nGenerated,2024-07-01\n,Q1,,Q2\nRegion,Revenue,Cost,Revenue\nEU,10,4,12\nUS,20,9,25\n"
+
+	testCases := []struct {
+		desc      string
+		input     string
+		options   []Option
+		expected  []Quarter
+		expectErr bool
+	}{
+		{
+			desc:    "header row with stacked header",
+			input:   report,
+			options: []Option{HeaderRow(4), StackedHeader(2)},
+			expected: []Quarter{
+				{Region: "EU", Q1Revenue: 10, Q1Cost: 4, Q2Revenue: 12, Line: 6},
+				{Region: "US", Q1Revenue: 20, Q1Cost: 9, Q2Revenue: 25, Line: 7},
+			},
+		},
+		{
+			desc:    "header row only",
+			input:   "Title\nRegion,Q1.Revenue\nEU,10\n",
+			options: []Option{HeaderRow(2)},
+			expected: []Quarter{
+				{Region: "EU", Q1Revenue: 10, Line: 3},
+			},
+		},
+		{
+			desc:      "header row on a blank line",
+			input:     report,
+			options:   []Option{HeaderRow(2)},
+			expectErr: true,
+		},
+		{
+			desc:      "header row past the end",
+			input:     report,
+			options:   []Option{HeaderRow(20)},
+			expectErr: true,
+		},
+		{
+			desc:      "stacked header longer than the file",
+			input:     ",Q1\n",
+			options:   []Option{StackedHeader(2)},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var quarters []Quarter
+			err := NewDecoder(tc.options...).NewReader(strings.NewReader(tc.input)).UnmarshalCSV(&quarters)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, quarters)
+			}
+		})
+	}
+}
@@ -2,20 +2,49 @@ package isly
 
 import (
 	"encoding/hex"
+	"fmt"
 	"strings"
 )
 
-func islyParseHex(value string) []byte {
-	value = strings.TrimPrefix(strings.TrimSpace(value), "0x")
+// islyParseHex decodes "0xDEADBEEF", "0XDEADBEEF", "\xDE\xAD\xBE\xEF", "DE:AD:BE:EF" or "DE AD BE EF".
+// A missing leading zero is added to odd length values and groups.
+func islyParseHex(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	original := value
 
-	if len(value)%2 != 0 {
-		value = "0" + value
+	for _, prefix := range []string{"0x", "0X"} {
+		value = strings.TrimPrefix(value, prefix)
 	}
 
-	bytes, err := hex.DecodeString(value)
-	if err != nil {
-		return nil
+	var groups []string
+	switch {
+	case strings.Contains(value, `\x`) || strings.Contains(value, `\X`):
+		value = strings.ReplaceAll(value, `\X`, `\x`)
+		groups = strings.Split(strings.TrimPrefix(value, `\x`), `\x`)
+	case strings.ContainsAny(value, ": -"):
+		groups = strings.FieldsFunc(value, func(r rune) bool {
+			return r == ':' || r == ' ' || r == '-'
+		})
+	default:
+		groups = []string{value}
 	}
 
-	return bytes
+	var result []byte
+	for _, group := range groups {
+		if len(group)%2 != 0 {
+			group = "0" + group
+		}
+
+		bytes, err := hex.DecodeString(group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse hex value '%s': %w", original, err)
+		}
+		result = append(result, bytes...)
+	}
+
+	if result == nil {
+		result = []byte{}
+	}
+
+	return result, nil
 }
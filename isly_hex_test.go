@@ -8,9 +8,10 @@ import (
 
 func TestIslyParseHex(t *testing.T) {
 	testCases := []struct {
-		desc     string
-		input    string
-		expected []byte
+		desc      string
+		input     string
+		expected  []byte
+		expectErr bool
 	}{
 		{
 			desc:     "empty hex string",
@@ -68,14 +69,14 @@ func TestIslyParseHex(t *testing.T) {
 			expected: []byte{0xFF, 0xFF},
 		},
 		{
-			desc:     "invalid hex characters",
-			input:    "0xGGHH",
-			expected: nil,
+			desc:      "invalid hex characters",
+			input:     "0xGGHH",
+			expectErr: true,
 		},
 		{
-			desc:     "completely invalid input",
-			input:    "hello",
-			expected: nil,
+			desc:      "completely invalid input",
+			input:     "hello",
+			expectErr: true,
 		},
 		{
 			desc:     "single digit",
@@ -87,15 +88,46 @@ func TestIslyParseHex(t *testing.T) {
 			input:    "0x",
 			expected: []byte{},
 		},
+		{
+			desc:     "uppercase prefix",
+			input:    "0XDEADBEEF",
+			expected: []byte{0xDE, 0xAD, 0xBE, 0xEF},
+		},
+		{
+			desc:     "escaped bytes",
+			input:    `\xDE\xAD\xbe\xef`,
+			expected: []byte{0xDE, 0xAD, 0xBE, 0xEF},
+		},
+		{
+			desc:     "colon separated",
+			input:    "DE:AD:BE:EF",
+			expected: []byte{0xDE, 0xAD, 0xBE, 0xEF},
+		},
+		{
+			desc:     "space separated",
+			input:    "de ad be ef",
+			expected: []byte{0xDE, 0xAD, 0xBE, 0xEF},
+		},
+		{
+			desc:     "dash separated with short group",
+			input:    "0A-B-0C",
+			expected: []byte{0x0A, 0x0B, 0x0C},
+		},
+		{
+			desc:      "invalid group",
+			input:     "DE:XX",
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			result := islyParseHex(tc.input)
+			result, err := islyParseHex(tc.input)
 
-			if tc.expected == nil {
-				assert.Nil(t, result, "Expected nil result for invalid input")
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error for invalid input")
 			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
 				assert.Equal(t, tc.expected, result, "Hex parsing result mismatch")
 
 				// Additional verification for byte length
@@ -0,0 +1,49 @@
+package isly
+
+import (
+	"reflect"
+)
+
+// RowContext describes the CSV row a struct was decoded from.
+type RowContext struct {
+	Row    int      // data row, starting at 1
+	Line   int      // line of the file the row starts on, starting at 1
+	Source string   // file the row was read from, empty for a Reader
+	Header []string // CSV header
+	Record []string // raw cells of the row
+}
+
+// Validator is implemented by structs that check themselves once every field is set,
+// e.g. that end_date comes after start_date. UnmarshalCSV calls it after AfterUnmarshalCSV.
+type Validator interface {
+	Validate() error
+}
+
+// AfterUnmarshaler is implemented by structs that need the raw row once their fields are
+// set, e.g. to fill derived fields. UnmarshalCSV calls it for every decoded row.
+type AfterUnmarshaler interface {
+	AfterUnmarshalCSV(ctx RowContext) error
+}
+
+// islyRunRowHooks calls AfterUnmarshalCSV and Validate on item when it implements them.
+// Both are looked up on the pointer so methods with pointer receivers are found.
+func islyRunRowHooks(item reflect.Value, ctx RowContext) error {
+	if !item.CanAddr() {
+		return nil
+	}
+	target := item.Addr().Interface()
+
+	if hook, ok := target.(AfterUnmarshaler); ok {
+		if err := hook.AfterUnmarshalCSV(ctx); err != nil {
+			return err
+		}
+	}
+
+	if validator, ok := target.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
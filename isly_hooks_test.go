@@ -0,0 +1,99 @@
+package isly
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hookBooking struct {
+	Guest string    `isly:"guest"`
+	Start time.Time `isly:"start, 2006-01-02"`
+	End   time.Time `isly:"end, 2006-01-02"`
+
+	Nights int
+	Source string
+	Row    int
+}
+
+func (b *hookBooking) AfterUnmarshalCSV(ctx RowContext) error {
+	b.Nights = int(b.End.Sub(b.Start).Hours() / 24)
+	b.Source = strings.Join(ctx.Record, ",")
+	b.Row = ctx.Row
+	return nil
+}
+
+func (b *hookBooking) Validate() error {
+	if !b.End.After(b.Start) {
+		return fmt.Errorf("end %s is not after start %s", b.End.Format("2006-01-02"), b.Start.Format("2006-01-02"))
+	}
+	return nil
+}
+
+type hookValueReceiver struct {
+	Name string `isly:"name"`
+}
+
+func (h hookValueReceiver) Validate() error {
+	if h.Name == "bad" {
+		return errors.New("bad name")
+	}
+	return nil
+}
+
+func TestUnmarshalCSVRowHooks(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		expectErr string
+		validate  func(t *testing.T, bookings []hookBooking)
+	}{
+		{
+			desc:  "derived fields are filled",
+			input: "guest,start,end\nJohn,2024-01-01,2024-01-04\nJane,2024-02-10,2024-02-11\n",
+			validate: func(t *testing.T, bookings []hookBooking) {
+				if assert.Len(t, bookings, 2) {
+					assert.Equal(t, 3, bookings[0].Nights)
+					assert.Equal(t, 1, bookings[0].Row)
+					assert.Equal(t, "John,2024-01-01,2024-01-04", bookings[0].Source)
+					assert.Equal(t, 1, bookings[1].Nights)
+					assert.Equal(t, 2, bookings[1].Row)
+				}
+			},
+		},
+		{
+			desc:      "cross-field check reports the row",
+			input:     "guest,start,end\nJohn,2024-01-01,2024-01-04\nJane,2024-02-10,2024-02-01\n",
+			expectErr: "error processing row 2: end 2024-02-01 is not after start 2024-02-10",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			component := NewIsly()
+			assert.NoError(t, component.ReadFile(writeTestCSV(t, tc.input)))
+
+			var bookings []hookBooking
+			err := component.UnmarshalCSV(&bookings)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err, "expected no error but got: %v", err)
+			tc.validate(t, bookings)
+		})
+	}
+}
+
+func TestUnmarshalCSVRowHooksSingleStruct(t *testing.T) {
+	component := NewIsly()
+	assert.NoError(t, component.ReadFile(writeTestCSV(t, "name\nbad\n")))
+
+	var row hookValueReceiver
+	assert.EqualError(t, component.UnmarshalCSV(&row), "error processing row 1: bad name")
+}
@@ -1,27 +1,629 @@
 package isly
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"reflect"
-	"regexp"
+	"strconv"
 	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
-func islyParseJSON(value string, fieldType reflect.Type) reflect.Value {
-	value = strings.ReplaceAll(value, "'", "\"")
+type islyJSONOptions struct {
+	strict    bool // object keys that match no field are errors
+	useNumber bool // numbers in interface{} values are kept as json.Number
+}
+
+func islyJSONOptionsFromTag(tag islyTag) islyJSONOptions {
+	return islyJSONOptions{
+		strict:    tag.hasOption("strict"),
+		useNumber: tag.hasOption("usenumber"),
+	}
+}
+
+func islyParseJSON(value string, fieldType reflect.Type, opts islyJSONOptions) (reflect.Value, error) {
+	if strings.TrimSpace(value) == "" {
+		return reflect.Zero(fieldType), nil
+	}
+
+	normalized, err := islyRelaxedJSON(value)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to parse json: %w", err)
+	}
+
+	newObj := reflect.New(fieldType)
+
+	// structs tagged with `isly` are matched by their isly names
+	if islyHasTaggedStruct(fieldType, map[reflect.Type]bool{}) {
+		var raw interface{}
+		if err := islyDecodeJSON(normalized, &raw, islyJSONOptions{useNumber: true}); err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to decode json: %w", err)
+		}
+		if err := islyAssignJSON(newObj.Elem(), raw, opts); err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to decode json into %v: %w", fieldType, err)
+		}
+		return newObj.Elem(), nil
+	}
+
+	if err := islyDecodeJSON(normalized, newObj.Interface(), opts); err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to decode json into %v: %w", fieldType, err)
+	}
+
+	return newObj.Elem(), nil
+}
+
+func islyDecodeJSON(data []byte, target interface{}, opts islyJSONOptions) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if opts.strict {
+		decoder.DisallowUnknownFields()
+	}
+	if opts.useNumber {
+		decoder.UseNumber()
+	}
+	return decoder.Decode(target)
+}
+
+// islyHasTaggedStruct reports whether t is, or contains, a struct with `isly` tagged fields.
+func islyHasTaggedStruct(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return islyHasTaggedStruct(t.Elem(), seen)
+	case reflect.Map:
+		return islyHasTaggedStruct(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if _, ok := t.Field(i).Tag.Lookup("isly"); ok {
+				return true
+			}
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if islyHasTaggedStruct(t.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// islyAssignJSON stores a decoded JSON value (numbers as json.Number) into dst, matching
+// struct fields by their isly tag, then json tag, then field name. String and number
+// values of isly tagged fields go through the same conversion as CSV cells, so date
+// layouts, durations, lists etc. work inside JSON too.
+func islyAssignJSON(dst reflect.Value, src interface{}, opts islyJSONOptions) error {
+	dstType := dst.Type()
+
+	if src == nil {
+		dst.Set(reflect.Zero(dstType))
+		return nil
+	}
+
+	if !islyHasTaggedStruct(dstType, map[reflect.Type]bool{}) {
+		// plain types are handled by encoding/json
+		data, err := json.Marshal(src)
+		if err != nil {
+			return err
+		}
+		return islyDecodeJSON(data, dst.Addr().Interface(), opts)
+	}
+
+	switch dstType.Kind() {
+	case reflect.Ptr:
+		ptr := reflect.New(dstType.Elem())
+		if err := islyAssignJSON(ptr.Elem(), src, opts); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+
+	case reflect.Slice, reflect.Array:
+		items, ok := src.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected array for %v, got %T", dstType, src)
+		}
+
+		if dstType.Kind() == reflect.Slice {
+			dst.Set(reflect.MakeSlice(dstType, len(items), len(items)))
+		} else if len(items) > dstType.Len() {
+			return fmt.Errorf("array has %d elements, %v holds %d", len(items), dstType, dstType.Len())
+		}
+
+		for i, item := range items {
+			if err := islyAssignJSON(dst.Index(i), item, opts); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+
+	case reflect.Map:
+		obj, ok := src.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected object for %v, got %T", dstType, src)
+		}
+
+		mapValue := reflect.MakeMapWithSize(dstType, len(obj))
+		for k, item := range obj {
+			key := reflect.New(dstType.Key()).Elem()
+			if err := islyParseListElement(key, islyListToken{text: k, quoted: true}, islyListOptions{}); err != nil {
+				return fmt.Errorf("key '%s': %w", k, err)
+			}
+			elem := reflect.New(dstType.Elem()).Elem()
+			if err := islyAssignJSON(elem, item, opts); err != nil {
+				return fmt.Errorf("key '%s': %w", k, err)
+			}
+			mapValue.SetMapIndex(key, elem)
+		}
+		dst.Set(mapValue)
+
+	case reflect.Struct:
+		obj, ok := src.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected object for %v, got %T", dstType, src)
+		}
+		return islyAssignJSONStruct(dst, obj, opts)
+
+	default:
+		return fmt.Errorf("unsupported json target %v", dstType)
+	}
+
+	return nil
+}
+
+func islyAssignJSONStruct(dst reflect.Value, obj map[string]interface{}, opts islyJSONOptions) error {
+	dstType := dst.Type()
+	used := make(map[string]bool, len(obj))
+
+	for i := 0; i < dstType.NumField(); i++ {
+		field := dst.Field(i)
+		structField := dstType.Field(i)
+
+		if !field.CanSet() {
+			continue
+		}
+
+		name := structField.Name
+		rawTag, hasIslyTag := structField.Tag.Lookup("isly")
+		parsedTag := islyParseTag(rawTag)
+		if hasIslyTag && parsedTag.name != "" {
+			name = parsedTag.name
+		} else if jsonTag, ok := structField.Tag.Lookup("json"); ok {
+			jsonName, _, _ := strings.Cut(jsonTag, ",")
+			if jsonName == "-" {
+				continue
+			}
+			if jsonName != "" {
+				name = jsonName
+			}
+		}
+
+		key, found := name, false
+		if _, found = obj[name]; !found {
+			// case-insensitive match, like encoding/json
+			for k := range obj {
+				if strings.EqualFold(k, name) {
+					key, found = k, true
+					break
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+		used[key] = true
+
+		var err error
+		switch item := obj[key].(type) {
+		case string:
+			if hasIslyTag {
+				err = islyParseField(field, item, parsedTag)
+			} else {
+				err = islyAssignJSON(field, item, opts)
+			}
+		case json.Number:
+			if hasIslyTag && field.Kind() != reflect.Interface {
+				err = islyParseField(field, item.String(), parsedTag)
+			} else {
+				err = islyAssignJSON(field, item, opts)
+			}
+		default:
+			err = islyAssignJSON(field, item, opts)
+		}
+		if err != nil {
+			return fmt.Errorf("field '%s': %w", name, err)
+		}
+	}
+
+	if opts.strict {
+		for k := range obj {
+			if !used[k] {
+				return fmt.Errorf("unknown field '%s' for %v", k, dstType)
+			}
+		}
+	}
+
+	return nil
+}
+
+// islyRelaxedJSON rewrites JSON5 and Python literals into standard JSON. On top of JSON it accepts
+//   - single quoted strings and unquoted or numeric object keys
+//   - True/False/None
+//   - trailing commas, // and /* */ comments
+//   - Python tuples as arrays
+//   - hex numbers, leading "+" and leading or trailing decimal points
+func islyRelaxedJSON(value string) ([]byte, error) {
+	p := &islyJSONParser{input: value}
+
+	if err := p.parseValue(); err != nil {
+		return nil, err
+	}
+
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected '%c' after value", p.input[p.pos])
+	}
+
+	return p.out.Bytes(), nil
+}
+
+type islyJSONParser struct {
+	input string
+	pos   int
+	out   bytes.Buffer
+}
 
-	re := regexp.MustCompile(`{([^{}]*)`)
-	value = re.ReplaceAllStringFunc(value, func(match string) string {
-		keyRegex := regexp.MustCompile(`(\w+)(\s*:)`)
-		return keyRegex.ReplaceAllString(match, "\"$1\"$2")
-	})
+func (p *islyJSONParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips whitespace and comments.
+func (p *islyJSONParser) skipSpace() error {
+	for p.pos < len(p.input) {
+		switch c := p.input[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case strings.HasPrefix(p.input[p.pos:], "//"):
+			end := strings.IndexByte(p.input[p.pos:], '\n')
+			if end < 0 {
+				p.pos = len(p.input)
+			} else {
+				p.pos += end + 1
+			}
+		case strings.HasPrefix(p.input[p.pos:], "/*"):
+			end := strings.Index(p.input[p.pos+2:], "*/")
+			if end < 0 {
+				return p.errorf("unterminated comment")
+			}
+			p.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *islyJSONParser) parseValue() error {
+	if err := p.skipSpace(); err != nil {
+		return err
+	}
+	if p.pos >= len(p.input) {
+		return p.errorf("unexpected end of input")
+	}
+
+	switch c := p.input[p.pos]; {
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		return p.parseArray(']')
+	case c == '(':
+		return p.parseArray(')')
+	case c == '"' || c == '\'':
+		s, err := p.parseString()
+		if err != nil {
+			return err
+		}
+		p.writeString(s)
+		return nil
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case islyIsJSONIdentifierByte(c):
+		word := p.parseIdentifier()
+		switch word {
+		case "true", "True":
+			p.out.WriteString("true")
+		case "false", "False":
+			p.out.WriteString("false")
+		case "null", "None":
+			p.out.WriteString("null")
+		case "NaN", "Infinity", "inf", "nan":
+			return p.errorf("'%s' can't be represented in JSON", word)
+		default:
+			return p.errorf("unexpected identifier '%s'", word)
+		}
+		return nil
+	default:
+		return p.errorf("unexpected '%c'", c)
+	}
+}
+
+func (p *islyJSONParser) parseObject() error {
+	// skip '{'
+	p.pos++
+	p.out.WriteByte('{')
+
+	first := true
+	for {
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.input) {
+			return p.errorf("unterminated object")
+		}
+		if p.input[p.pos] == '}' {
+			p.pos++
+			p.out.WriteByte('}')
+			return nil
+		}
+
+		if !first {
+			if p.input[p.pos] != ',' {
+				return p.errorf("expected ',' or '}' in object, got '%c'", p.input[p.pos])
+			}
+			p.pos++
+
+			// trailing comma
+			if err := p.skipSpace(); err != nil {
+				return err
+			}
+			if p.pos < len(p.input) && p.input[p.pos] == '}' {
+				continue
+			}
+			p.out.WriteByte(',')
+		}
+		first = false
+
+		key, err := p.parseKey()
+		if err != nil {
+			return err
+		}
+		p.writeString(key)
+
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.input) || p.input[p.pos] != ':' {
+			return p.errorf("expected ':' after key '%s'", key)
+		}
+		p.pos++
+		p.out.WriteByte(':')
+
+		if err := p.parseValue(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *islyJSONParser) parseKey() (string, error) {
+	if err := p.skipSpace(); err != nil {
+		return "", err
+	}
+	if p.pos >= len(p.input) {
+		return "", p.errorf("unterminated object")
+	}
+
+	c := p.input[p.pos]
+	switch {
+	case c == '"' || c == '\'':
+		return p.parseString()
+	case islyIsJSONIdentifierByte(c) || c == '-' || c == '+' || c == '.':
+		// bare identifiers and Python's numeric keys
+		start := p.pos
+		for p.pos < len(p.input) && (islyIsJSONIdentifierByte(p.input[p.pos]) || strings.IndexByte("-+.", p.input[p.pos]) >= 0) {
+			p.pos++
+		}
+		return p.input[start:p.pos], nil
+	default:
+		return "", p.errorf("unexpected '%c' in object key", c)
+	}
+}
+
+func (p *islyJSONParser) parseArray(closer byte) error {
+	// skip '[' or '('
+	p.pos++
+	p.out.WriteByte('[')
+
+	first := true
+	for {
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.input) {
+			return p.errorf("unterminated array")
+		}
+		if p.input[p.pos] == closer {
+			p.pos++
+			p.out.WriteByte(']')
+			return nil
+		}
+
+		if !first {
+			if p.input[p.pos] != ',' {
+				return p.errorf("expected ',' or '%c' in array, got '%c'", closer, p.input[p.pos])
+			}
+			p.pos++
+
+			// trailing comma
+			if err := p.skipSpace(); err != nil {
+				return err
+			}
+			if p.pos < len(p.input) && p.input[p.pos] == closer {
+				continue
+			}
+			p.out.WriteByte(',')
+		}
+		first = false
+
+		if err := p.parseValue(); err != nil {
+			return err
+		}
+	}
+}
 
-	newObj := reflect.New(fieldType).Interface()
+func (p *islyJSONParser) parseString() (string, error) {
+	start := p.pos
+	quote := p.input[p.pos]
+	p.pos++
 
-	err := json.Unmarshal([]byte(value), newObj)
+	var text strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+
+		if c == quote {
+			p.pos++
+			return text.String(), nil
+		}
+
+		if c != '\\' {
+			text.WriteByte(c)
+			p.pos++
+			continue
+		}
+
+		if p.pos+1 >= len(p.input) {
+			break
+		}
+
+		next := p.input[p.pos+1]
+		p.pos += 2
+		switch next {
+		case 'b':
+			text.WriteByte('\b')
+		case 'f':
+			text.WriteByte('\f')
+		case 'n':
+			text.WriteByte('\n')
+		case 'r':
+			text.WriteByte('\r')
+		case 't':
+			text.WriteByte('\t')
+		case 'v':
+			text.WriteByte('\v')
+		case '0':
+			text.WriteByte(0)
+		case 'x':
+			r, err := p.parseHexEscape(2)
+			if err != nil {
+				return "", err
+			}
+			text.WriteRune(r)
+		case 'u':
+			r, err := p.parseHexEscape(4)
+			if err != nil {
+				return "", err
+			}
+			// surrogate pair
+			if utf16.IsSurrogate(r) && strings.HasPrefix(p.input[p.pos:], "\\u") {
+				p.pos += 2
+				low, err := p.parseHexEscape(4)
+				if err != nil {
+					return "", err
+				}
+				r = utf16.DecodeRune(r, low)
+			}
+			text.WriteRune(r)
+		case '\n':
+			// line continuation
+		default:
+			// \" \' \\ \/ and any other escaped character stand for themselves
+			text.WriteByte(next)
+		}
+	}
+
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+func (p *islyJSONParser) parseHexEscape(digits int) (rune, error) {
+	if p.pos+digits > len(p.input) {
+		return 0, p.errorf("invalid escape sequence")
+	}
+
+	r, err := strconv.ParseUint(p.input[p.pos:p.pos+digits], 16, 32)
 	if err != nil {
-		return reflect.Value{}
+		return 0, p.errorf("invalid escape sequence")
+	}
+	p.pos += digits
+
+	return rune(r), nil
+}
+
+func (p *islyJSONParser) parseNumber() error {
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte("0123456789abcdefABCDEFxX+-._", p.input[p.pos]) >= 0 {
+		p.pos++
 	}
+	raw := p.input[start:p.pos]
+
+	number := strings.ReplaceAll(strings.TrimPrefix(raw, "+"), "_", "")
+
+	negative := strings.HasPrefix(number, "-")
+	digits := strings.TrimPrefix(number, "-")
+
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		hexVal, err := strconv.ParseUint(digits[2:], 16, 64)
+		if err != nil {
+			return p.errorf("invalid number '%s'", raw)
+		}
+		digits = strconv.FormatUint(hexVal, 10)
+	} else {
+		if strings.HasPrefix(digits, ".") {
+			digits = "0" + digits
+		}
+		digits = strings.Replace(digits, ".e", ".0e", 1)
+		digits = strings.Replace(digits, ".E", ".0E", 1)
+		digits = strings.TrimSuffix(digits, ".")
+
+		// drop leading zeros: 007 -> 7
+		for len(digits) > 1 && digits[0] == '0' && digits[1] >= '0' && digits[1] <= '9' {
+			digits = digits[1:]
+		}
+	}
+
+	if negative {
+		digits = "-" + digits
+	}
+
+	if !json.Valid([]byte(digits)) {
+		return p.errorf("invalid number '%s'", raw)
+	}
+
+	p.out.WriteString(digits)
+	return nil
+}
+
+func (p *islyJSONParser) parseIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.input) && islyIsJSONIdentifierByte(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *islyJSONParser) writeString(s string) {
+	// json.Marshal can't fail on a string; invalid UTF-8 is replaced
+	if !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	}
+	quoted, _ := json.Marshal(s)
+	p.out.Write(quoted)
+}
 
-	return reflect.ValueOf(newObj).Elem()
+func islyIsJSONIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= utf8.RuneSelf
 }
@@ -1,8 +1,10 @@
 package isly
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -22,18 +24,26 @@ func TestIslyParseJSON(t *testing.T) {
 		Scores []int      `json:"scores"`
 	}
 
+	type IslyItem struct {
+		SKU     string                 `isly:"sku"`
+		Qty     int                    `isly:"qty"`
+		Shipped time.Time              `isly:"shipped, 02/01/2006"`
+		Timeout time.Duration          `isly:"timeout, unit=s"`
+		Extra   map[string]interface{} `isly:"extra"`
+	}
+
 	testCases := []struct {
 		desc      string
 		input     string
 		fieldType reflect.Type
-		isValid   bool
+		opts      islyJSONOptions
+		expectErr bool
 		validate  func(t *testing.T, result reflect.Value)
 	}{
 		{
 			desc:      "simple struct with single quotes",
 			input:     "{'name': 'John', 'age': 30, 'balance': 100.50, 'active': true}",
 			fieldType: reflect.TypeOf(TestStruct{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				obj := result.Interface().(TestStruct)
 				assert.Equal(t, "John", obj.Name)
@@ -46,7 +56,6 @@ func TestIslyParseJSON(t *testing.T) {
 			desc:      "simple struct with double quotes",
 			input:     `{"name": "Jane", "age": 25, "balance": 200.75, "active": false}`,
 			fieldType: reflect.TypeOf(TestStruct{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				obj := result.Interface().(TestStruct)
 				assert.Equal(t, "Jane", obj.Name)
@@ -59,7 +68,6 @@ func TestIslyParseJSON(t *testing.T) {
 			desc:      "struct with unquoted keys",
 			input:     "{name: 'Alice', age: 22, balance: 150.25, active: true}",
 			fieldType: reflect.TypeOf(TestStruct{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				obj := result.Interface().(TestStruct)
 				assert.Equal(t, "Alice", obj.Name)
@@ -72,7 +80,6 @@ func TestIslyParseJSON(t *testing.T) {
 			desc:      "struct with mixed quotes and spacing",
 			input:     "{name:'Bob',age:   35,  balance: 300.00,'active':false}",
 			fieldType: reflect.TypeOf(TestStruct{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				obj := result.Interface().(TestStruct)
 				assert.Equal(t, "Bob", obj.Name)
@@ -85,7 +92,6 @@ func TestIslyParseJSON(t *testing.T) {
 			desc:      "nested struct",
 			input:     "{'id': 1, 'user': {'name': 'Charlie', 'age': 40, 'balance': 500.0, 'active': true}, 'tags': ['developer', 'go'], 'scores': [85, 90, 95]}",
 			fieldType: reflect.TypeOf(NestedStruct{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				obj := result.Interface().(NestedStruct)
 				assert.Equal(t, 1, obj.ID)
@@ -101,7 +107,6 @@ func TestIslyParseJSON(t *testing.T) {
 			desc:      "empty struct",
 			input:     "{}",
 			fieldType: reflect.TypeOf(TestStruct{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				obj := result.Interface().(TestStruct)
 				assert.Equal(t, "", obj.Name)
@@ -114,21 +119,18 @@ func TestIslyParseJSON(t *testing.T) {
 			desc:      "invalid json format",
 			input:     "{name: 'Invalid, missing closing brace",
 			fieldType: reflect.TypeOf(TestStruct{}),
-			isValid:   false,
-			validate:  func(t *testing.T, result reflect.Value) {},
+			expectErr: true,
 		},
 		{
 			desc:      "invalid field type",
 			input:     "{'name': 'John', 'age': 'thirty', 'balance': 100.50, 'active': true}",
 			fieldType: reflect.TypeOf(TestStruct{}),
-			isValid:   false,
-			validate:  func(t *testing.T, result reflect.Value) {},
+			expectErr: true,
 		},
 		{
 			desc:      "primitive types - string",
 			input:     "'Hello World'",
 			fieldType: reflect.TypeOf(""),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				assert.Equal(t, "Hello World", result.Interface().(string))
 			},
@@ -137,7 +139,6 @@ func TestIslyParseJSON(t *testing.T) {
 			desc:      "primitive types - int",
 			input:     "42",
 			fieldType: reflect.TypeOf(0),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				assert.Equal(t, 42, result.Interface().(int))
 			},
@@ -146,7 +147,6 @@ func TestIslyParseJSON(t *testing.T) {
 			desc:      "slice of strings",
 			input:     "['apple', 'banana', 'cherry']",
 			fieldType: reflect.TypeOf([]string{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				assert.Equal(t, []string{"apple", "banana", "cherry"}, result.Interface().([]string))
 			},
@@ -155,7 +155,6 @@ func TestIslyParseJSON(t *testing.T) {
 			desc:      "slice of integers",
 			input:     "[1, 2, 3, 4, 5]",
 			fieldType: reflect.TypeOf([]int{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				assert.Equal(t, []int{1, 2, 3, 4, 5}, result.Interface().([]int))
 			},
@@ -164,19 +163,179 @@ func TestIslyParseJSON(t *testing.T) {
 			desc:      "empty string",
 			input:     "",
 			fieldType: reflect.TypeOf(TestStruct{}),
-			isValid:   false,
-			validate:  func(t *testing.T, result reflect.Value) {},
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, TestStruct{}, result.Interface().(TestStruct))
+			},
+		},
+		{
+			desc:      "apostrophes and colons in values",
+			input:     `{"name": "O'Brien", 'url': 'http://x.example/a:b'}`,
+			fieldType: reflect.TypeOf(map[string]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := map[string]string{"name": "O'Brien", "url": "http://x.example/a:b"}
+				assert.Equal(t, expected, result.Interface().(map[string]string))
+			},
+		},
+		{
+			desc:      "escaped quotes in single quoted string",
+			input:     `{'note': 'it\'s "quoted"'}`,
+			fieldType: reflect.TypeOf(map[string]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]string{"note": `it's "quoted"`}, result.Interface().(map[string]string))
+			},
+		},
+		{
+			desc:      "python literals",
+			input:     "{'active': True, 'deleted': False, 'parent': None}",
+			fieldType: reflect.TypeOf(map[string]interface{}{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := map[string]interface{}{"active": true, "deleted": false, "parent": nil}
+				assert.Equal(t, expected, result.Interface().(map[string]interface{}))
+			},
+		},
+		{
+			desc:      "trailing commas and comments",
+			input:     "{name: 'Eve', /* age */ age: 28, tags: ['a', 'b',], // done\n}",
+			fieldType: reflect.TypeOf(map[string]interface{}{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := map[string]interface{}{"name": "Eve", "age": float64(28), "tags": []interface{}{"a", "b"}}
+				assert.Equal(t, expected, result.Interface().(map[string]interface{}))
+			},
+		},
+		{
+			desc:      "JSON5 numbers",
+			input:     "[0x1F, +5, .5, 2., -0.25]",
+			fieldType: reflect.TypeOf([]float64{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []float64{31, 5, 0.5, 2, -0.25}, result.Interface().([]float64))
+			},
+		},
+		{
+			desc:      "python tuple and numeric keys",
+			input:     "{1: (1, 2), 2: ()}",
+			fieldType: reflect.TypeOf(map[int][]int{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[int][]int{1: {1, 2}, 2: {}}, result.Interface().(map[int][]int))
+			},
+		},
+		{
+			desc:      "unicode escapes",
+			input:     `'caf\u00e9 \ud83d\ude00'`,
+			fieldType: reflect.TypeOf(""),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, "café 😀", result.Interface().(string))
+			},
+		},
+		{
+			desc:      "unquoted string value",
+			input:     "{role: manager}",
+			fieldType: reflect.TypeOf(map[string]string{}),
+			expectErr: true,
+		},
+		{
+			desc:      "trailing garbage",
+			input:     "{'a': 1} extra",
+			fieldType: reflect.TypeOf(map[string]int{}),
+			expectErr: true,
+		},
+		{
+			desc:      "NaN is not representable",
+			input:     "[NaN]",
+			fieldType: reflect.TypeOf([]float64{}),
+			expectErr: true,
+		},
+		{
+			desc:      "use number keeps ids exact",
+			input:     "{'id': 9007199254740993, 'ratio': 0.5}",
+			fieldType: reflect.TypeOf(map[string]interface{}{}),
+			opts:      islyJSONOptions{useNumber: true},
+			validate: func(t *testing.T, result reflect.Value) {
+				obj := result.Interface().(map[string]interface{})
+				assert.Equal(t, json.Number("9007199254740993"), obj["id"])
+				assert.Equal(t, json.Number("0.5"), obj["ratio"])
+			},
+		},
+		{
+			desc:      "without use number ids are float64",
+			input:     "{'id': 42}",
+			fieldType: reflect.TypeOf(map[string]interface{}{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, float64(42), result.Interface().(map[string]interface{})["id"])
+			},
+		},
+		{
+			desc:      "strict rejects unknown fields",
+			input:     "{'name': 'John', 'nickname': 'Johnny'}",
+			fieldType: reflect.TypeOf(TestStruct{}),
+			opts:      islyJSONOptions{strict: true},
+			expectErr: true,
+		},
+		{
+			desc:      "non strict ignores unknown fields",
+			input:     "{'name': 'John', 'nickname': 'Johnny'}",
+			fieldType: reflect.TypeOf(TestStruct{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, "John", result.Interface().(TestStruct).Name)
+			},
+		},
+		{
+			desc:      "array of objects into slice of structs",
+			input:     "[{'name': 'A', 'age': 1}, {'name': 'B', 'age': 2}]",
+			fieldType: reflect.TypeOf([]TestStruct{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := []TestStruct{{Name: "A", Age: 1}, {Name: "B", Age: 2}}
+				assert.Equal(t, expected, result.Interface().([]TestStruct))
+			},
+		},
+		{
+			desc:      "array of objects into isly tagged structs",
+			input:     "[{sku: 'X1', qty: '3', shipped: '15/05/2023', timeout: 30, extra: {id: 7}}, {sku: 'X2', qty: 4}]",
+			fieldType: reflect.TypeOf([]IslyItem{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				items := result.Interface().([]IslyItem)
+				assert.Len(t, items, 2)
+				assert.Equal(t, "X1", items[0].SKU)
+				assert.Equal(t, 3, items[0].Qty)
+				assert.Equal(t, time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC), items[0].Shipped)
+				assert.Equal(t, 30*time.Second, items[0].Timeout)
+				assert.Equal(t, map[string]interface{}{"id": float64(7)}, items[0].Extra)
+				assert.Equal(t, "X2", items[1].SKU)
+				assert.Equal(t, 4, items[1].Qty)
+			},
+		},
+		{
+			desc:      "isly tagged struct with use number",
+			input:     "{sku: 'X1', extra: {id: 9007199254740993}}",
+			fieldType: reflect.TypeOf(IslyItem{}),
+			opts:      islyJSONOptions{useNumber: true},
+			validate: func(t *testing.T, result reflect.Value) {
+				item := result.Interface().(IslyItem)
+				assert.Equal(t, map[string]interface{}{"id": json.Number("9007199254740993")}, item.Extra)
+			},
+		},
+		{
+			desc:      "isly tagged struct strict",
+			input:     "{sku: 'X1', color: 'red'}",
+			fieldType: reflect.TypeOf(IslyItem{}),
+			opts:      islyJSONOptions{strict: true},
+			expectErr: true,
+		},
+		{
+			desc:      "isly tagged struct invalid value",
+			input:     "[{sku: 'X1', qty: 'three'}]",
+			fieldType: reflect.TypeOf([]IslyItem{}),
+			expectErr: true,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			result := islyParseJSON(tc.input, tc.fieldType)
+			result, err := islyParseJSON(tc.input, tc.fieldType, tc.opts)
 
-			if !tc.isValid {
-				assert.Equal(t, reflect.Value{}, result, "expected empty reflect.Value for invalid input")
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
 			} else {
-				assert.NotEqual(t, reflect.Value{}, result, "expected non-empty reflect.Value for valid input")
+				assert.NoError(t, err, "expected no error but got: %v", err)
 				tc.validate(t, result)
 			}
 		})
@@ -0,0 +1,131 @@
+package isly
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// islyParseKV parses "key=value;key=value" cells into a map. Pairs are split on opts.sep
+// (";" by default) and keys from values on kvSep ("=" by default). Quoted values may
+// contain either separator.
+func islyParseKV(value string, fieldType reflect.Type, opts islyListOptions, kvSep string) (reflect.Value, error) {
+	if fieldType.Kind() != reflect.Map {
+		return reflect.Value{}, fmt.Errorf("kv tag requires a map field, got %v", fieldType)
+	}
+
+	if opts.sep == "" {
+		opts.sep = ";"
+	}
+	if kvSep == "" {
+		kvSep = "="
+	}
+
+	mapValue := reflect.MakeMap(fieldType)
+
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		value = strings.TrimSpace(value[1 : len(value)-1])
+	}
+	if value == "" {
+		return mapValue, nil
+	}
+
+	for i, pair := range islySplitKVPairs(value, opts.sep, kvSep) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			if opts.strict {
+				return reflect.Value{}, fmt.Errorf("empty pair at position %d", i)
+			}
+			continue
+		}
+
+		rawKey, rawValue, ok := strings.Cut(pair, kvSep)
+		if !ok {
+			if opts.strict {
+				return reflect.Value{}, fmt.Errorf("pair '%s' is missing '%s'", pair, kvSep)
+			}
+			// a bare key is kept with an empty value
+			rawValue = ""
+		}
+
+		keyToken, err := islyKVToken(rawKey, opts)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("pair %d key: %w", i, err)
+		}
+		valueToken, err := islyKVToken(rawValue, opts)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("pair %d value: %w", i, err)
+		}
+
+		key := reflect.New(fieldType.Key()).Elem()
+		if err := islyParseListElement(key, keyToken, opts); err != nil {
+			return reflect.Value{}, fmt.Errorf("key '%s': %w", keyToken.text, err)
+		}
+
+		if opts.strict && mapValue.MapIndex(key).IsValid() {
+			return reflect.Value{}, fmt.Errorf("duplicate key '%s'", keyToken.text)
+		}
+
+		elem := reflect.New(fieldType.Elem()).Elem()
+		if err := islyParseListElement(elem, valueToken, opts); err != nil {
+			return reflect.Value{}, fmt.Errorf("key '%s': %w", keyToken.text, err)
+		}
+
+		mapValue.SetMapIndex(key, elem)
+	}
+
+	return mapValue, nil
+}
+
+// islySplitKVPairs splits value on sep outside of quotes.
+func islySplitKVPairs(value string, sep string, kvSep string) []string {
+	var pairs []string
+	var quote byte
+
+	start := 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"':
+			// only a quote opening a key or value starts a quoted section, O'Brien stays as-is
+			if prev := strings.TrimRight(value[start:i], " \t"); prev == "" || strings.HasSuffix(prev, kvSep) {
+				quote = c
+			}
+		case strings.HasPrefix(value[i:], sep):
+			pairs = append(pairs, value[start:i])
+			i += len(sep) - 1
+			start = i + 1
+		}
+	}
+
+	return append(pairs, value[start:])
+}
+
+// islyKVToken trims a key or value and unescapes it when quoted.
+func islyKVToken(raw string, opts islyListOptions) (islyListToken, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || (raw[0] != '\'' && raw[0] != '"') {
+		return islyListToken{text: raw}, nil
+	}
+
+	token, end, err := islyReadQuotedListElement(raw, 0, opts.sep, opts.strict)
+	if err != nil {
+		return islyListToken{}, err
+	}
+	if end < len(raw) && opts.strict {
+		return islyListToken{}, fmt.Errorf("unexpected text after quoted '%s'", token.text)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,140 @@
+package isly
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslyParseKV(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		fieldType reflect.Type
+		opts      islyListOptions
+		kvSep     string
+		expectErr bool
+		validate  func(t *testing.T, result reflect.Value)
+	}{
+		{
+			desc:      "string values",
+			input:     "color=red;size=XL",
+			fieldType: reflect.TypeOf(map[string]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]string{"color": "red", "size": "XL"}, result.Interface())
+			},
+		},
+		{
+			desc:      "spaces and trailing separator",
+			input:     " color = red ; size = XL ; ",
+			fieldType: reflect.TypeOf(map[string]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]string{"color": "red", "size": "XL"}, result.Interface())
+			},
+		},
+		{
+			desc:      "typed values",
+			input:     "width=10;height=20",
+			fieldType: reflect.TypeOf(map[string]int{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]int{"width": 10, "height": 20}, result.Interface())
+			},
+		},
+		{
+			desc:      "time values",
+			input:     "start=2024-01-02;end=2024-02-03",
+			fieldType: reflect.TypeOf(map[string]time.Time{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := map[string]time.Time{
+					"start": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+					"end":   time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+				}
+				assert.Equal(t, expected, result.Interface())
+			},
+		},
+		{
+			desc:      "quoted values may contain separators",
+			input:     `note='a=b;c';owner="O'Brien"`,
+			fieldType: reflect.TypeOf(map[string]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]string{"note": "a=b;c", "owner": "O'Brien"}, result.Interface())
+			},
+		},
+		{
+			desc:      "unquoted apostrophe",
+			input:     "owner=O'Brien;team=ops",
+			fieldType: reflect.TypeOf(map[string]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]string{"owner": "O'Brien", "team": "ops"}, result.Interface())
+			},
+		},
+		{
+			desc:      "custom separators",
+			input:     "{color: red | size: XL}",
+			fieldType: reflect.TypeOf(map[string]string{}),
+			opts:      islyListOptions{sep: "|"},
+			kvSep:     ":",
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]string{"color": "red", "size": "XL"}, result.Interface())
+			},
+		},
+		{
+			desc:      "empty cell",
+			input:     "",
+			fieldType: reflect.TypeOf(map[string]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]string{}, result.Interface())
+			},
+		},
+		{
+			desc:      "bare key",
+			input:     "featured;color=red",
+			fieldType: reflect.TypeOf(map[string]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]string{"featured": "", "color": "red"}, result.Interface())
+			},
+		},
+		{
+			desc:      "invalid value",
+			input:     "width=ten",
+			fieldType: reflect.TypeOf(map[string]int{}),
+			expectErr: true,
+		},
+		{
+			desc:      "not a map",
+			input:     "a=1",
+			fieldType: reflect.TypeOf([]string{}),
+			expectErr: true,
+		},
+		{
+			desc:      "strict - bare key",
+			input:     "featured;color=red",
+			fieldType: reflect.TypeOf(map[string]string{}),
+			opts:      islyListOptions{strict: true},
+			expectErr: true,
+		},
+		{
+			desc:      "strict - duplicate key",
+			input:     "color=red;color=blue",
+			fieldType: reflect.TypeOf(map[string]string{}),
+			opts:      islyListOptions{strict: true},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := islyParseKV(tc.input, tc.fieldType, tc.opts, tc.kvSep)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.fieldType, result.Type(), "type mismatch in result")
+				tc.validate(t, result)
+			}
+		})
+	}
+}
@@ -1,75 +1,417 @@
 package isly
 
 import (
+	"encoding"
+	"fmt"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 )
 
-func islyParseList(value string, fieldType reflect.Type) reflect.Value {
-	if fieldType.Kind() != reflect.Slice {
-		return reflect.Value{}
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+	// Separator names usable in `sep=`, a literal "," can't be written inside a tag
+	listSeparators = map[string]string{
+		"comma":     ",",
+		"semicolon": ";",
+		"pipe":      "|",
+		"tab":       "\t",
+		"space":     " ",
+	}
+)
+
+type islyListOptions struct {
+	sep    string // element separator, "," by default
+	layout string // date layout for time.Time elements
+	epoch  string // excel epoch when layout is "excel"
+	unit   string // unit of bare numbers for time.Duration elements
+	strict bool   // reject malformed list literals
+}
+
+func islyListOptionsFromTag(tag islyTag) islyListOptions {
+	sep := tag.option("sep")
+	if named, ok := listSeparators[strings.ToLower(sep)]; ok {
+		sep = named
+	}
+
+	return islyListOptions{
+		sep:    sep,
+		layout: tag.option("format"),
+		epoch:  tag.option("epoch"),
+		unit:   tag.option("unit"),
+		strict: tag.hasOption("strict"),
+	}
+}
+
+func islyParseList(value string, fieldType reflect.Type, opts islyListOptions) (reflect.Value, error) {
+	isSet := islyIsSetType(fieldType)
+	if fieldType.Kind() != reflect.Slice && fieldType.Kind() != reflect.Array && !isSet {
+		return reflect.Value{}, fmt.Errorf("list tag requires a slice, array or set field, got %v", fieldType)
+	}
+
+	if opts.sep == "" {
+		opts.sep = ","
 	}
 
 	value = strings.TrimSpace(value)
-	value = strings.Trim(value, "[]")
-	if value == "" {
-		return reflect.MakeSlice(fieldType, 0, 0)
+
+	// Python set literal
+	if isSet && strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		value = "[" + value[1:len(value)-1] + "]"
 	}
 
-	var elements []string
+	hasOpen, hasClose := strings.HasPrefix(value, "["), strings.HasSuffix(value, "]")
+	switch {
+	case hasOpen && hasClose && islyOuterBrackets(value):
+		value = value[1 : len(value)-1]
+	case opts.strict && hasOpen != hasClose:
+		return reflect.Value{}, fmt.Errorf("unbalanced brackets in list '%s'", value)
+	case hasOpen && !hasClose:
+		value = value[1:]
+	case hasClose && !hasOpen:
+		value = value[:len(value)-1]
+	}
+	value = strings.TrimSpace(value)
 
-	if strings.Contains(value, "'") || strings.Contains(value, "\"") {
-		re := regexp.MustCompile(`'([^']*)'|"([^"]*)"`)
-		matches := re.FindAllStringSubmatch(value, -1)
+	var elements []islyListToken
+	if value != "" {
+		var err error
+		elements, err = islyTokenizeList(value, opts.sep, opts.strict)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+	}
 
-		for _, match := range matches {
-			if match[1] != "" {
-				elements = append(elements, match[1])
-			} else if match[2] != "" {
-				elements = append(elements, match[2])
-			}
+	if isSet {
+		return islyBuildSet(fieldType, elements, opts)
+	}
+
+	var listValue reflect.Value
+	if fieldType.Kind() == reflect.Array {
+		if len(elements) > fieldType.Len() {
+			return reflect.Value{}, fmt.Errorf("list has %d elements, array %v holds %d", len(elements), fieldType, fieldType.Len())
 		}
+		listValue = reflect.New(fieldType).Elem()
 	} else {
-		parts := strings.Split(value, ",")
-		for _, part := range parts {
-			elements = append(elements, strings.TrimSpace(part))
+		listValue = reflect.MakeSlice(fieldType, len(elements), len(elements))
+	}
+
+	for i, elem := range elements {
+		if err := islyParseListElement(listValue.Index(i), elem, opts); err != nil {
+			return reflect.Value{}, fmt.Errorf("list element %d: %w", i, err)
 		}
 	}
 
-	sliceValue := reflect.MakeSlice(fieldType, len(elements), len(elements))
+	return listValue, nil
+}
+
+// islyIsSetType reports whether fieldType is a set, map[T]struct{} or map[T]bool.
+func islyIsSetType(fieldType reflect.Type) bool {
+	if fieldType.Kind() != reflect.Map {
+		return false
+	}
+
 	elemType := fieldType.Elem()
+	return elemType.Kind() == reflect.Bool || (elemType.Kind() == reflect.Struct && elemType.NumField() == 0)
+}
+
+func islyBuildSet(fieldType reflect.Type, elements []islyListToken, opts islyListOptions) (reflect.Value, error) {
+	setValue := reflect.MakeMapWithSize(fieldType, len(elements))
+
+	member := reflect.Zero(fieldType.Elem())
+	if fieldType.Elem().Kind() == reflect.Bool {
+		member = reflect.ValueOf(true).Convert(fieldType.Elem())
+	}
 
 	for i, elem := range elements {
-		elemValue := sliceValue.Index(i)
+		key := reflect.New(fieldType.Key()).Elem()
+		if err := islyParseListElement(key, elem, opts); err != nil {
+			return reflect.Value{}, fmt.Errorf("list element %d: %w", i, err)
+		}
+		setValue.SetMapIndex(key, member)
+	}
+
+	return setValue, nil
+}
+
+// islyListToken is a single list element. Quoted elements are already unescaped,
+// nested lists are kept as their raw "[...]" text.
+type islyListToken struct {
+	text   string
+	quoted bool
+}
+
+// islyTokenizeList splits the inside of a Python-style (['a', "b"]) or JSON-style (["a", "b"])
+// list literal. Unquoted elements are trimmed, a trailing separator is ignored.
+// In strict mode empty elements, unterminated quotes, unbalanced brackets, unknown
+// escapes and text after a closing quote are errors instead of being kept as-is.
+func islyTokenizeList(value string, sep string, strict bool) ([]islyListToken, error) {
+	var tokens []islyListToken
+
+	i := 0
+	for {
+		i = islySkipListSpace(value, i)
+
+		// end of input right after a separator: trailing separator
+		if i >= len(value) {
+			if len(tokens) > 0 {
+				return tokens, nil
+			}
+			break
+		}
+
+		var token islyListToken
+		var err error
+
+		switch value[i] {
+		case '\'', '"':
+			token, i, err = islyReadQuotedListElement(value, i, sep, strict)
+		default:
+			token, i, err = islyReadBareListElement(value, i, sep, strict)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if strict && !token.quoted && token.text == "" {
+			return nil, fmt.Errorf("empty list element at position %d", len(tokens))
+		}
+		tokens = append(tokens, token)
+
+		if i >= len(value) {
+			break
+		}
+
+		// skip the separator
+		i += len(sep)
+	}
+
+	return tokens, nil
+}
+
+// islyOuterBrackets reports whether the "[" at value[0] is closed by the final "]",
+// so "[1], [2]" is not mistaken for a single list. Unmatched quotes (O'Brien) are tolerated.
+func islyOuterBrackets(value string) bool {
+	depth := 0
+	var quote byte
 
-		switch elemType.Kind() {
-		case reflect.String:
-			elemValue.SetString(elem)
+	for i := 0; i < len(value); i++ {
+		c := value[i]
 
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			intVal, err := strconv.ParseInt(elem, 10, 64)
-			if err != nil {
-				return reflect.Value{}
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
 			}
-			elemValue.SetInt(intVal)
+			continue
+		}
+
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i == len(value)-1
+			}
+		}
+	}
+
+	return true
+}
+
+func islySkipListSpace(value string, i int) int {
+	for i < len(value) && (value[i] == ' ' || value[i] == '\t' || value[i] == '\n' || value[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// islyReadQuotedListElement reads a quoted element starting at value[start] and returns the
+// position of the following separator (or len(value)).
+func islyReadQuotedListElement(value string, start int, sep string, strict bool) (islyListToken, int, error) {
+	quote := value[start]
+
+	var text strings.Builder
+	i := start + 1
+	closed := false
+
+	for i < len(value) {
+		c := value[i]
+
+		if c == quote {
+			closed = true
+			i++
+			break
+		}
+
+		if c != '\\' || i+1 >= len(value) {
+			text.WriteByte(c)
+			i++
+			continue
+		}
+
+		// escape sequence
+		next := value[i+1]
+		switch next {
+		case '\\', '\'', '"', '/':
+			text.WriteByte(next)
+			i += 2
+		case 'n':
+			text.WriteByte('\n')
+			i += 2
+		case 't':
+			text.WriteByte('\t')
+			i += 2
+		case 'r':
+			text.WriteByte('\r')
+			i += 2
+		case 'b':
+			text.WriteByte('\b')
+			i += 2
+		case 'f':
+			text.WriteByte('\f')
+			i += 2
+		case 'u':
+			if i+6 <= len(value) {
+				if r, err := strconv.ParseUint(value[i+2:i+6], 16, 32); err == nil {
+					text.WriteRune(rune(r))
+					i += 6
+					continue
+				}
+			}
+			if strict {
+				return islyListToken{}, 0, fmt.Errorf("invalid unicode escape at offset %d", i)
+			}
+			text.WriteByte(c)
+			i++
+		default:
+			if strict {
+				return islyListToken{}, 0, fmt.Errorf("unknown escape '\\%c' at offset %d", next, i)
+			}
+			// keep unknown escapes as written, like Python does
+			text.WriteByte(c)
+			i++
+		}
+	}
+
+	if !closed && strict {
+		return islyListToken{}, 0, fmt.Errorf("unterminated quoted list element starting at offset %d", start)
+	}
 
-		case reflect.Float32, reflect.Float64:
-			floatVal, err := strconv.ParseFloat(elem, 64)
-			if err != nil {
-				return reflect.Value{}
+	// anything between the closing quote and the separator
+	i = islySkipListSpace(value, i)
+	if i < len(value) && !strings.HasPrefix(value[i:], sep) {
+		if strict {
+			return islyListToken{}, 0, fmt.Errorf("unexpected '%c' after quoted list element at offset %d", value[i], i)
+		}
+		rest, next, err := islyReadBareListElement(value, i, sep, strict)
+		if err != nil {
+			return islyListToken{}, 0, err
+		}
+		text.WriteString(rest.text)
+		i = next
+	}
+
+	return islyListToken{text: text.String(), quoted: true}, i, nil
+}
+
+// islyReadBareListElement reads an unquoted element, including nested [...] lists, up to the
+// next separator outside brackets and quotes.
+func islyReadBareListElement(value string, start int, sep string, strict bool) (islyListToken, int, error) {
+	depth := 0
+	var quote byte
+
+	i := start
+	for ; i < len(value); i++ {
+		c := value[i]
+
+		// quotes only matter inside nested lists, O'Brien is a valid bare element
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
 			}
-			elemValue.SetFloat(floatVal)
+			continue
+		}
 
-		case reflect.Bool:
-			boolVal, err := strconv.ParseBool(elem)
-			if err != nil {
-				return reflect.Value{}
+		switch {
+		case depth > 0 && (c == '\'' || c == '"'):
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			if depth == 0 {
+				if strict {
+					return islyListToken{}, 0, fmt.Errorf("unbalanced ']' at offset %d", i)
+				}
+				continue
 			}
-			elemValue.SetBool(boolVal)
+			depth--
+		case depth == 0 && strings.HasPrefix(value[i:], sep):
+			return islyListToken{text: strings.TrimSpace(value[start:i])}, i, nil
 		}
 	}
 
-	return sliceValue
+	if strict && (depth > 0 || quote != 0) {
+		return islyListToken{}, 0, fmt.Errorf("unbalanced nested list starting at offset %d", start)
+	}
+
+	return islyListToken{text: strings.TrimSpace(value[start:])}, i, nil
+}
+
+func islyParseListElement(elemValue reflect.Value, token islyListToken, opts islyListOptions) error {
+	elemType := elemValue.Type()
+	elem := token.text
+
+	switch {
+	case elemType.Kind() == reflect.Ptr:
+		if !token.quoted && (elem == "" || elem == "null" || elem == "None") {
+			elemValue.Set(reflect.Zero(elemType))
+			return nil
+		}
+		ptr := reflect.New(elemType.Elem())
+		if err := islyParseListElement(ptr.Elem(), token, opts); err != nil {
+			return err
+		}
+		elemValue.Set(ptr)
+
+	// checked before the kinds below so types like net.IP ([]byte) or a string type
+	// with its own UnmarshalText are decoded by it; time.Time keeps the layout handling
+	case elemType != timeType && reflect.PointerTo(elemType).Implements(textUnmarshalerType):
+		return elemValue.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(elem))
+
+	case elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array:
+		nestedValue, err := islyParseList(elem, elemType, opts)
+		if err != nil {
+			return err
+		}
+		elemValue.Set(nestedValue)
+
+	case elemType.Kind() == reflect.String:
+		elemValue.SetString(elem)
+
+	case elemType == timeType && opts.layout == "excel":
+		return islyParseExcelField(elemValue, elem, opts.epoch)
+
+	case elemType == durationType:
+		d, err := islyParseDuration(elem, opts.unit)
+		if err != nil {
+			return err
+		}
+		elemValue.SetInt(int64(d))
+
+	case elemType == timeType:
+		return islyParsePrimitiveData(elemValue, elem, elemType, opts.layout)
+
+	default:
+		return islyParsePrimitiveData(elemValue, elem, elemType, "")
+	}
+
+	return nil
 }
@@ -1,8 +1,13 @@
 package isly
 
 import (
+	"fmt"
+	"net"
+	"net/netip"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -12,14 +17,14 @@ func TestIslyParseList(t *testing.T) {
 		desc      string
 		input     string
 		fieldType reflect.Type
-		isValid   bool
+		opts      islyListOptions
+		expectErr bool
 		validate  func(t *testing.T, result reflect.Value)
 	}{
 		{
 			desc:      "list of strings with single quotes",
 			input:     "['apple', 'banana', 'cherry']",
 			fieldType: reflect.TypeOf([]string{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []string{"apple", "banana", "cherry"}
 				actual := result.Interface().([]string)
@@ -30,7 +35,6 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "list of strings with double quotes",
 			input:     `["dog", "cat", "bird"]`,
 			fieldType: reflect.TypeOf([]string{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []string{"dog", "cat", "bird"}
 				actual := result.Interface().([]string)
@@ -41,7 +45,6 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "list of integers",
 			input:     "[1, 2, 3, 4, 5]",
 			fieldType: reflect.TypeOf([]int{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []int{1, 2, 3, 4, 5}
 				actual := result.Interface().([]int)
@@ -52,7 +55,6 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "list of int64",
 			input:     "[9223372036854775800, 9223372036854775801]",
 			fieldType: reflect.TypeOf([]int64{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []int64{9223372036854775800, 9223372036854775801}
 				actual := result.Interface().([]int64)
@@ -63,7 +65,6 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "list of floats",
 			input:     "[1.1, 2.2, 3.3, 4.4, 5.5]",
 			fieldType: reflect.TypeOf([]float64{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []float64{1.1, 2.2, 3.3, 4.4, 5.5}
 				actual := result.Interface().([]float64)
@@ -74,7 +75,6 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "list of booleans",
 			input:     "[true, false, true, true, false]",
 			fieldType: reflect.TypeOf([]bool{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []bool{true, false, true, true, false}
 				actual := result.Interface().([]bool)
@@ -85,7 +85,6 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "empty list",
 			input:     "[]",
 			fieldType: reflect.TypeOf([]string{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []string{}
 				actual := result.Interface().([]string)
@@ -97,7 +96,6 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "list with spaces",
 			input:     "  [  1  ,  2  ,  3  ]  ",
 			fieldType: reflect.TypeOf([]int{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []int{1, 2, 3}
 				actual := result.Interface().([]int)
@@ -108,7 +106,6 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "list without brackets",
 			input:     "1, 2, 3, 4",
 			fieldType: reflect.TypeOf([]int{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []int{1, 2, 3, 4}
 				actual := result.Interface().([]int)
@@ -119,7 +116,6 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "list with mixed quotes",
 			input:     "['apple', \"banana\", 'cherry']",
 			fieldType: reflect.TypeOf([]string{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []string{"apple", "banana", "cherry"}
 				actual := result.Interface().([]string)
@@ -130,35 +126,30 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "invalid field type (not a slice)",
 			input:     "[1, 2, 3]",
 			fieldType: reflect.TypeOf(""),
-			isValid:   false,
-			validate:  func(t *testing.T, result reflect.Value) {},
+			expectErr: true,
 		},
 		{
 			desc:      "invalid element type for int slice",
 			input:     "[1, two, 3]",
 			fieldType: reflect.TypeOf([]int{}),
-			isValid:   false,
-			validate:  func(t *testing.T, result reflect.Value) {},
+			expectErr: true,
 		},
 		{
 			desc:      "invalid element type for float slice",
 			input:     "[1.1, 2.two, 3.3]",
 			fieldType: reflect.TypeOf([]float64{}),
-			isValid:   false,
-			validate:  func(t *testing.T, result reflect.Value) {},
+			expectErr: true,
 		},
 		{
 			desc:      "invalid element type for bool slice",
 			input:     "[true, not_bool, false]",
 			fieldType: reflect.TypeOf([]bool{}),
-			isValid:   false,
-			validate:  func(t *testing.T, result reflect.Value) {},
+			expectErr: true,
 		},
 		{
 			desc:      "string list without quotes",
 			input:     "apple, banana, cherry",
 			fieldType: reflect.TypeOf([]string{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []string{"apple", "banana", "cherry"}
 				actual := result.Interface().([]string)
@@ -169,7 +160,6 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "mixed spacing in comma separated list",
 			input:     "apple,banana,  cherry,  date",
 			fieldType: reflect.TypeOf([]string{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []string{"apple", "banana", "cherry", "date"}
 				actual := result.Interface().([]string)
@@ -180,26 +170,430 @@ func TestIslyParseList(t *testing.T) {
 			desc:      "single item list",
 			input:     "[42]",
 			fieldType: reflect.TypeOf([]int{}),
-			isValid:   true,
 			validate: func(t *testing.T, result reflect.Value) {
 				expected := []int{42}
 				actual := result.Interface().([]int)
 				assert.Equal(t, expected, actual)
 			},
 		},
+		{
+			desc:      "semicolon separator",
+			input:     "a; b; c",
+			fieldType: reflect.TypeOf([]string{}),
+			opts:      islyListOptions{sep: ";"},
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"a", "b", "c"}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "pipe separator keeps commas",
+			input:     "[1,5|2,5]",
+			fieldType: reflect.TypeOf([]string{}),
+			opts:      islyListOptions{sep: "|"},
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"1,5", "2,5"}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "list of uint",
+			input:     "[1, 2, 255]",
+			fieldType: reflect.TypeOf([]uint8{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []uint8{1, 2, 255}, result.Interface().([]uint8))
+			},
+		},
+		{
+			desc:      "invalid uint element",
+			input:     "[1, -2]",
+			fieldType: reflect.TypeOf([]uint{}),
+			expectErr: true,
+		},
+		{
+			desc:      "list of time.Time",
+			input:     "['2023-05-15', '2024-01-02']",
+			fieldType: reflect.TypeOf([]time.Time{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := []time.Time{
+					time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				}
+				assert.Equal(t, expected, result.Interface().([]time.Time))
+			},
+		},
+		{
+			desc:      "list of time.Time with layout",
+			input:     "15.05.2023|02.01.2024",
+			fieldType: reflect.TypeOf([]time.Time{}),
+			opts:      islyListOptions{sep: "|", layout: "02.01.2006"},
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := []time.Time{
+					time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				}
+				assert.Equal(t, expected, result.Interface().([]time.Time))
+			},
+		},
+		{
+			desc:      "list of excel serial dates",
+			input:     "[45123.5, 2023-07-17]",
+			fieldType: reflect.TypeOf([]time.Time{}),
+			opts:      islyListOptions{layout: "excel"},
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := []time.Time{
+					time.Date(2023, 7, 16, 12, 0, 0, 0, time.UTC),
+					time.Date(2023, 7, 17, 0, 0, 0, 0, time.UTC),
+				}
+				assert.Equal(t, expected, result.Interface().([]time.Time))
+			},
+		},
+		{
+			desc:      "list of excel serial dates with 1904 epoch",
+			input:     "[0, 43661]",
+			fieldType: reflect.TypeOf([]time.Time{}),
+			opts:      islyListOptionsFromTag(islyParseTag("dates, list, format=excel, epoch=1904")),
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := []time.Time{
+					time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC),
+					time.Date(2023, 7, 16, 0, 0, 0, 0, time.UTC),
+				}
+				assert.Equal(t, expected, result.Interface().([]time.Time))
+			},
+		},
+		{
+			desc:      "list of durations with unit",
+			input:     "[1, 2, 1.5s]",
+			fieldType: reflect.TypeOf([]time.Duration{}),
+			opts:      islyListOptionsFromTag(islyParseTag("e, list, unit=ms")),
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := []time.Duration{time.Millisecond, 2 * time.Millisecond, 1500 * time.Millisecond}
+				assert.Equal(t, expected, result.Interface().([]time.Duration))
+			},
+		},
+		{
+			desc:      "list of pointers",
+			input:     "[1, null, 3]",
+			fieldType: reflect.TypeOf([]*int{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				actual := result.Interface().([]*int)
+				assert.Len(t, actual, 3)
+				assert.Equal(t, 1, *actual[0])
+				assert.Nil(t, actual[1])
+				assert.Equal(t, 3, *actual[2])
+			},
+		},
+		{
+			desc:      "list of TextUnmarshaler",
+			input:     "[10.0.0.1, ::1]",
+			fieldType: reflect.TypeOf([]netip.Addr{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1")}
+				assert.Equal(t, expected, result.Interface().([]netip.Addr))
+			},
+		},
+		{
+			desc:      "list of net.IP",
+			input:     "[10.0.0.1, 10.0.0.2]",
+			fieldType: reflect.TypeOf([]net.IP{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				actual := result.Interface().([]net.IP)
+				if assert.Len(t, actual, 2) {
+					assert.True(t, net.ParseIP("10.0.0.1").Equal(actual[0]))
+					assert.True(t, net.ParseIP("10.0.0.2").Equal(actual[1]))
+				}
+			},
+		},
+		{
+			desc:      "list of string type with UnmarshalText",
+			input:     "['active', \"Closed\"]",
+			fieldType: reflect.TypeOf([]listTestStatus{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []listTestStatus{"ACTIVE", "CLOSED"}, result.Interface().([]listTestStatus))
+			},
+		},
+		{
+			desc:      "string type with UnmarshalText rejects a value",
+			input:     "[active, pending]",
+			fieldType: reflect.TypeOf([]listTestStatus{}),
+			expectErr: true,
+		},
+		{
+			desc:      "invalid TextUnmarshaler element",
+			input:     "[10.0.0.1, not-an-ip]",
+			fieldType: reflect.TypeOf([]netip.Addr{}),
+			expectErr: true,
+		},
+		{
+			desc:      "nested list",
+			input:     "[[1, 2], [3], []]",
+			fieldType: reflect.TypeOf([][]int{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, [][]int{{1, 2}, {3}, {}}, result.Interface().([][]int))
+			},
+		},
+		{
+			desc:      "nested list of strings",
+			input:     "[['a', 'b'], ['c']]",
+			fieldType: reflect.TypeOf([][]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, result.Interface().([][]string))
+			},
+		},
+		{
+			desc:      "array",
+			input:     "[1, 2, 3]",
+			fieldType: reflect.TypeOf([3]int{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, [3]int{1, 2, 3}, result.Interface().([3]int))
+			},
+		},
+		{
+			desc:      "array with fewer elements",
+			input:     "[1]",
+			fieldType: reflect.TypeOf([3]int{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, [3]int{1, 0, 0}, result.Interface().([3]int))
+			},
+		},
+		{
+			desc:      "array with too many elements",
+			input:     "[1, 2, 3, 4]",
+			fieldType: reflect.TypeOf([3]int{}),
+			expectErr: true,
+		},
+		{
+			desc:      "slice of arrays",
+			input:     "[[1, 2], [3, 4]]",
+			fieldType: reflect.TypeOf([][2]float64{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, [][2]float64{{1, 2}, {3, 4}}, result.Interface().([][2]float64))
+			},
+		},
+		{
+			desc:      "empty quoted elements are kept",
+			input:     `['a', '', "c", ""]`,
+			fieldType: reflect.TypeOf([]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"a", "", "c", ""}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "apostrophe inside double quotes",
+			input:     `["O'Brien", 'Smith']`,
+			fieldType: reflect.TypeOf([]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"O'Brien", "Smith"}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "escaped quotes",
+			input:     `['O\'Brien', "say \"hi\""]`,
+			fieldType: reflect.TypeOf([]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"O'Brien", `say "hi"`}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "JSON escapes",
+			input:     `["line\nbreak", "tab\there", "caf\u00e9", "back\\slash"]`,
+			fieldType: reflect.TypeOf([]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"line\nbreak", "tab\there", "café", `back\slash`}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "separator inside quotes",
+			input:     `['a, b', "c"]`,
+			fieldType: reflect.TypeOf([]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"a, b", "c"}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "quoted and unquoted elements mixed",
+			input:     `['a', b, "c", O'Brien]`,
+			fieldType: reflect.TypeOf([]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"a", "b", "c", "O'Brien"}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "quoted whitespace is preserved",
+			input:     `['  padded  ']`,
+			fieldType: reflect.TypeOf([]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"  padded  "}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "trailing separator",
+			input:     "[1, 2, 3, ]",
+			fieldType: reflect.TypeOf([]int{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []int{1, 2, 3}, result.Interface().([]int))
+			},
+		},
+		{
+			desc:      "python literals",
+			input:     "[True, False, None]",
+			fieldType: reflect.TypeOf([]*bool{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				actual := result.Interface().([]*bool)
+				assert.Len(t, actual, 3)
+				assert.True(t, *actual[0])
+				assert.False(t, *actual[1])
+				assert.Nil(t, actual[2])
+			},
+		},
+		{
+			desc:      "quoted None is a string",
+			input:     "['None']",
+			fieldType: reflect.TypeOf([]*string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				actual := result.Interface().([]*string)
+				assert.Len(t, actual, 1)
+				assert.Equal(t, "None", *actual[0])
+			},
+		},
+		{
+			desc:      "nested list with brackets inside quotes",
+			input:     `[['a]', 'b'], ['[c']]`,
+			fieldType: reflect.TypeOf([][]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, [][]string{{"a]", "b"}, {"[c"}}, result.Interface().([][]string))
+			},
+		},
+		{
+			desc:      "nested lists without outer brackets",
+			input:     "[1, 2], [3]",
+			fieldType: reflect.TypeOf([][]int{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, [][]int{{1, 2}, {3}}, result.Interface().([][]int))
+			},
+		},
+		{
+			desc:      "lenient unterminated quote",
+			input:     "['a', 'b",
+			fieldType: reflect.TypeOf([]string{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"a", "b"}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "strict - valid input",
+			input:     `['a', "b", c]`,
+			fieldType: reflect.TypeOf([]string{}),
+			opts:      islyListOptions{strict: true},
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, []string{"a", "b", "c"}, result.Interface().([]string))
+			},
+		},
+		{
+			desc:      "strict - unterminated quote",
+			input:     "['a', 'b]",
+			fieldType: reflect.TypeOf([]string{}),
+			opts:      islyListOptions{strict: true},
+			expectErr: true,
+		},
+		{
+			desc:      "strict - empty element",
+			input:     "[1, , 3]",
+			fieldType: reflect.TypeOf([]int{}),
+			opts:      islyListOptions{strict: true},
+			expectErr: true,
+		},
+		{
+			desc:      "strict - text after closing quote",
+			input:     "['a'b, 'c']",
+			fieldType: reflect.TypeOf([]string{}),
+			opts:      islyListOptions{strict: true},
+			expectErr: true,
+		},
+		{
+			desc:      "strict - unbalanced brackets",
+			input:     "[1, 2",
+			fieldType: reflect.TypeOf([]int{}),
+			opts:      islyListOptions{strict: true},
+			expectErr: true,
+		},
+		{
+			desc:      "strict - unknown escape",
+			input:     `['a\qb']`,
+			fieldType: reflect.TypeOf([]string{}),
+			opts:      islyListOptions{strict: true},
+			expectErr: true,
+		},
+		{
+			desc:      "set of strings",
+			input:     "['red', 'green', 'red']",
+			fieldType: reflect.TypeOf(map[string]struct{}{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				expected := map[string]struct{}{"red": {}, "green": {}}
+				assert.Equal(t, expected, result.Interface().(map[string]struct{}))
+			},
+		},
+		{
+			desc:      "set of ints as map[int]bool",
+			input:     "1|2|3",
+			fieldType: reflect.TypeOf(map[int]bool{}),
+			opts:      islyListOptions{sep: "|"},
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[int]bool{1: true, 2: true, 3: true}, result.Interface().(map[int]bool))
+			},
+		},
+		{
+			desc:      "python set literal",
+			input:     "{'a', 'b'}",
+			fieldType: reflect.TypeOf(map[string]bool{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]bool{"a": true, "b": true}, result.Interface().(map[string]bool))
+			},
+		},
+		{
+			desc:      "empty set",
+			input:     "[]",
+			fieldType: reflect.TypeOf(map[string]struct{}{}),
+			validate: func(t *testing.T, result reflect.Value) {
+				assert.Equal(t, map[string]struct{}{}, result.Interface().(map[string]struct{}))
+			},
+		},
+		{
+			desc:      "invalid set element",
+			input:     "[1, x]",
+			fieldType: reflect.TypeOf(map[int]struct{}{}),
+			expectErr: true,
+		},
+		{
+			desc:      "map that is not a set",
+			input:     "[1, 2]",
+			fieldType: reflect.TypeOf(map[string]int{}),
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			result := islyParseList(tc.input, tc.fieldType)
+			result, err := islyParseList(tc.input, tc.fieldType, tc.opts)
 
-			if !tc.isValid {
-				assert.Equal(t, reflect.Value{}, result, "expected empty reflect.Value for invalid input")
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
 			} else {
-				assert.NotEqual(t, reflect.Value{}, result, "expected non-empty reflect.Value for valid input")
+				assert.NoError(t, err, "expected no error but got: %v", err)
 				assert.Equal(t, tc.fieldType, result.Type(), "type mismatch in result")
 				tc.validate(t, result)
 			}
 		})
 	}
 }
+
+// listTestStatus is a string type that normalizes and checks itself in UnmarshalText.
+type listTestStatus string
+
+func (s *listTestStatus) UnmarshalText(text []byte) error {
+	switch value := strings.ToUpper(string(text)); value {
+	case "ACTIVE", "CLOSED":
+		*s = listTestStatus(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown status '%s'", text)
+	}
+}
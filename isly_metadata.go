@@ -0,0 +1,100 @@
+package isly
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Source tells where a row came from. It can be filled by an `isly:",source"` field;
+// a string field gets only the file name.
+type Source struct {
+	File string // empty for a Reader
+	Line int    // line the row starts on, starting at 1
+}
+
+var sourceType = reflect.TypeOf(Source{})
+
+// Kinds of the `isly:",kind"` tags that are filled from the row instead of a column
+var islyMetadataKinds = map[string]bool{
+	"source": true,
+	"line":   true,
+	"raw":    true,
+	"extra":  true,
+	"remain": true,
+}
+
+// islySetMetadata fills the metadata fields of item from ctx. columns is the header the
+// row was bound with.
+func islySetMetadata(item reflect.Value, plan *islyStructPlan, ctx RowContext, columns *islyHeader) error {
+	for _, planned := range plan.fields {
+		if planned.meta == "" {
+			continue
+		}
+
+		field := item.Field(planned.index)
+		if !field.CanSet() {
+			continue
+		}
+
+		switch planned.meta {
+		case "source":
+			switch {
+			case field.Type() == sourceType:
+				field.Set(reflect.ValueOf(Source{File: ctx.Source, Line: ctx.Line}))
+			case field.Kind() == reflect.String:
+				field.SetString(ctx.Source)
+			default:
+				return fmt.Errorf("source field must be a string or isly.Source, got %v", field.Type())
+			}
+
+		case "line":
+			switch field.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				field.SetInt(int64(ctx.Line))
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				field.SetUint(uint64(ctx.Line))
+			default:
+				return fmt.Errorf("line field must be an integer, got %v", field.Type())
+			}
+
+		case "raw":
+			if field.Type() != reflect.TypeOf([]string(nil)) {
+				return fmt.Errorf("raw field must be []string, got %v", field.Type())
+			}
+			field.Set(reflect.ValueOf(append([]string(nil), ctx.Record...)))
+
+		case "extra", "remain":
+			// remain is the name used by other decoders for the same catch-all map
+			if field.Type() != reflect.TypeOf(map[string]string(nil)) {
+				return fmt.Errorf("%s field must be map[string]string, got %v", planned.meta, field.Type())
+			}
+			field.Set(reflect.ValueOf(islyExtraColumns(plan, ctx, columns)))
+		}
+	}
+
+	return nil
+}
+
+// islyExtraColumns returns the cells of the columns no field of plan is bound to, by
+// header name. A field with the all flag is bound to every column of its name, even
+// those renamed in DuplicateHeaderRename mode.
+func islyExtraColumns(plan *islyStructPlan, ctx RowContext, columns *islyHeader) map[string]string {
+	collected := make(map[int]bool)
+	for _, planned := range plan.fields {
+		if planned.meta != "" || !planned.tag.hasOption("all") {
+			continue
+		}
+		for _, index := range columns.duplicates[planned.tag.name] {
+			collected[index] = true
+		}
+	}
+
+	extra := make(map[string]string)
+	for j, name := range ctx.Header {
+		if plan.bound[name] || collected[j] || j >= len(ctx.Record) {
+			continue
+		}
+		extra[name] = ctx.Record[j]
+	}
+	return extra
+}
@@ -0,0 +1,95 @@
+package isly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalCSVMetadata(t *testing.T) {
+	type Customer struct {
+		ID    int               `isly:"id"`
+		Name  string            `isly:"name"`
+		Line  int               `isly:",line"`
+		Raw   []string          `isly:",raw"`
+		Extra map[string]string `isly:",extra"`
+	}
+
+	input := "# export\nid,name,region,note\n1,John,EU,\"two\nlines\"\n2,Jane,US,\n"
+
+	var customers []Customer
+	err := NewDecoder(SkipRows(1), WithCellTransform(func(column, value string) string {
+		return strings.ToUpper(value)
+	})).NewReader(strings.NewReader(input)).UnmarshalCSV(&customers)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Customer{
+		{
+			ID:    1,
+			Name:  "JOHN",
+			Line:  3,
+			Raw:   []string{"1", "John", "EU", "two\nlines"},
+			Extra: map[string]string{"region": "EU", "note": "two\nlines"},
+		},
+		{
+			ID:    2,
+			Name:  "JANE",
+			Line:  5,
+			Raw:   []string{"2", "Jane", "US", ""},
+			Extra: map[string]string{"region": "US", "note": ""},
+		},
+	}, customers)
+}
+
+func TestUnmarshalCSVMetadataTypes(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		target    interface{}
+		expectErr bool
+	}{
+		{desc: "uint line", target: &struct {
+			Line uint32 `isly:",line"`
+		}{}},
+		{desc: "string line", target: &struct {
+			Line string `isly:",line"`
+		}{}, expectErr: true},
+		{desc: "raw must be a string slice", target: &struct {
+			Raw []int `isly:",raw"`
+		}{}, expectErr: true},
+		{desc: "extra must be a string map", target: &struct {
+			Extra map[string]int `isly:",extra"`
+		}{}, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := NewDecoder().NewReader(strings.NewReader("id\n1\n")).UnmarshalCSV(tc.target)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalCSVRemain(t *testing.T) {
+	type Product struct {
+		SKU        string            `isly:"sku"`
+		Price      float64           `isly:"price"`
+		Attributes map[string]string `isly:",remain"`
+	}
+
+	input := "sku,color,price,size\nA1,red,9.5,M\nB2,,12,\n"
+
+	var products []Product
+	err := NewDecoder().NewReader(strings.NewReader(input)).UnmarshalCSV(&products)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Product{
+		{SKU: "A1", Price: 9.5, Attributes: map[string]string{"color": "red", "size": "M"}},
+		{SKU: "B2", Price: 12, Attributes: map[string]string{"color": "", "size": ""}},
+	}, products)
+}
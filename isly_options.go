@@ -0,0 +1,168 @@
+package isly
+
+import (
+	"context"
+	"encoding/csv"
+)
+
+// Option configures a component created by NewIsly.
+type Option func(*islyOptions)
+
+type islyOptions struct {
+	skipRows   int
+	skipFooter int
+	rowFilter  func(record []string) bool
+	transform  func(column, value string) string
+	progress   func(rows int, bytesRead int64)
+	zipEntry   string
+	encoding   Encoding
+	headerRow  int
+	headerRows int
+
+	duplicateHeaders DuplicateHeaderMode
+}
+
+// SkipRows skips the first n lines of the file, before the header is read.
+// Use it for titles or notes written above the table.
+func SkipRows(n int) Option {
+	return func(o *islyOptions) {
+		o.skipRows = n
+	}
+}
+
+// SkipFooter drops the last n data rows, e.g. a totals line at the end of an export.
+func SkipFooter(n int) Option {
+	return func(o *islyOptions) {
+		o.skipFooter = n
+	}
+}
+
+// WithRowFilter decodes only the data rows for which keep returns true. keep gets the
+// raw record, so comments and subtotal lines can be told apart before any conversion.
+func WithRowFilter(keep func(record []string) bool) Option {
+	return func(o *islyOptions) {
+		o.rowFilter = keep
+	}
+}
+
+// WithCellTransform rewrites every cell before it is converted. column is the header
+// of the cell; the value returned is what the field is parsed from.
+func WithCellTransform(transform func(column, value string) string) Option {
+	return func(o *islyOptions) {
+		o.transform = transform
+	}
+}
+
+// WithZipEntry selects the file ReadFile decodes from a zip archive, by name or by a
+// path.Match glob such as "*/orders_*.csv".
+func WithZipEntry(pattern string) Option {
+	return func(o *islyOptions) {
+		o.zipEntry = pattern
+	}
+}
+
+// WithEncoding sets the character encoding of the input, which is transcoded to UTF-8
+// before it is parsed. Names such as "latin1" and "cp1252" are accepted too. A byte
+// order mark in the input takes precedence.
+func WithEncoding(encoding Encoding) Option {
+	return func(o *islyOptions) {
+		o.encoding = encoding
+	}
+}
+
+// WithProgress calls report after every decoded row with the number of rows decoded so
+// far and the number of bytes read from the files or stream, before decompression, so
+// it can be compared with the file size. Input is read in blocks, so the count runs a
+// few kilobytes ahead of the rows and reaches the file size at the end. report runs on
+// the decoding goroutine and should return quickly.
+func WithProgress(report func(rows int, bytesRead int64)) Option {
+	return func(o *islyOptions) {
+		o.progress = report
+	}
+}
+
+// islyNewCSVReader prepares a csv.Reader for the options. Skipped, filtered, footer and
+// title rows often have a different number of cells, so the field count check is relaxed
+// whenever one of them is set.
+func (o islyOptions) islyNewCSVReader(reader *csv.Reader) *csv.Reader {
+	if o.skipRows > 0 || o.skipFooter > 0 || o.rowFilter != nil || o.headerRow > 0 || o.headerRows > 1 {
+		reader.FieldsPerRecord = -1
+	}
+	return reader
+}
+
+// transformRecord returns the cells the fields are parsed from. record is left untouched
+// so hooks still see the raw row.
+func (o islyOptions) transformRecord(header []string, record []string) []string {
+	if o.transform == nil {
+		return record
+	}
+
+	cells := make([]string, len(record))
+	for j, value := range record {
+		column := ""
+		if j < len(header) {
+			column = header[j]
+		}
+		cells[j] = o.transform(column, value)
+	}
+	return cells
+}
+
+// islyRowReader reads data rows with the row filter and SkipFooter applied.
+type islyRowReader struct {
+	ctx        context.Context
+	reader     *csv.Reader
+	rowFilter  func(record []string) bool
+	skipFooter int
+
+	// rows read ahead so the footer is never returned
+	buffered []islyRecord
+	row      int
+}
+
+type islyRecord struct {
+	cells []string
+	row   int
+	line  int
+}
+
+func newIslyRowReader(ctx context.Context, reader *csv.Reader, opts islyOptions) *islyRowReader {
+	return &islyRowReader{
+		ctx:        ctx,
+		reader:     reader,
+		rowFilter:  opts.rowFilter,
+		skipFooter: opts.skipFooter,
+	}
+}
+
+// next returns the next data row. Its row number counts from the first row after the
+// header, including the rows that are filtered out; its line is where it starts in the
+// file. It returns io.EOF at the end, and ctx.Err() once ctx is cancelled, which is
+// checked for every row read so a long run of filtered rows can be interrupted too.
+func (r *islyRowReader) next() (islyRecord, error) {
+	for {
+		if err := r.ctx.Err(); err != nil {
+			return islyRecord{}, err
+		}
+
+		for len(r.buffered) <= r.skipFooter {
+			record, err := r.reader.Read()
+			if err != nil {
+				// on io.EOF whatever is still buffered is the footer
+				return islyRecord{}, err
+			}
+			line, _ := r.reader.FieldPos(0)
+			r.row++
+			r.buffered = append(r.buffered, islyRecord{cells: record, row: r.row, line: line})
+		}
+
+		current := r.buffered[0]
+		r.buffered = r.buffered[1:]
+
+		if r.rowFilter != nil && !r.rowFilter(current.cells) {
+			continue
+		}
+		return current, nil
+	}
+}
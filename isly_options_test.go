@@ -0,0 +1,114 @@
+package isly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalCSVOptions(t *testing.T) {
+	type Sale struct {
+		Region string  `isly:"region"`
+		Amount float64 `isly:"amount"`
+	}
+
+	testCases := []struct {
+		desc      string
+		input     string
+		options   []Option
+		expected  []Sale
+		expectErr bool
+	}{
+		{
+			desc:     "skip title lines above the header",
+			input:    "Sales report\nGenerated 2024-01-01,by admin\nregion,amount\nnorth,10\nsouth,20\n",
+			options:  []Option{SkipRows(2)},
+			expected: []Sale{{"north", 10}, {"south", 20}},
+		},
+		{
+			desc:     "skip footer rows",
+			input:    "region,amount\nnorth,10\nsouth,20\nTotal,30\nend of report\n",
+			options:  []Option{SkipFooter(2)},
+			expected: []Sale{{"north", 10}, {"south", 20}},
+		},
+		{
+			desc:     "footer larger than the data",
+			input:    "region,amount\nnorth,10\n",
+			options:  []Option{SkipFooter(3)},
+			expected: []Sale{},
+		},
+		{
+			desc:  "row filter drops comments and subtotals",
+			input: "region,amount\n# imported manually\nnorth,10\nsubtotal,10\nsouth,20\n",
+			options: []Option{WithRowFilter(func(record []string) bool {
+				return !strings.HasPrefix(record[0], "#") && record[0] != "subtotal"
+			})},
+			expected: []Sale{{"north", 10}, {"south", 20}},
+		},
+		{
+			desc:  "cell transform runs before conversion",
+			input: "region,amount\n North ,\"1.234,50\"\n",
+			options: []Option{WithCellTransform(func(column, value string) string {
+				if column == "amount" {
+					return strings.NewReplacer(".", "", ",", ".").Replace(value)
+				}
+				return strings.ToLower(strings.TrimSpace(value))
+			})},
+			expected: []Sale{{"north", 1234.5}},
+		},
+		{
+			desc:  "all options together",
+			input: "Report\nregion,amount\nnorth,10\n;comment\nsouth,$20\nTotal,30\n",
+			options: []Option{
+				SkipRows(1),
+				SkipFooter(1),
+				WithRowFilter(func(record []string) bool { return !strings.HasPrefix(record[0], ";") }),
+				WithCellTransform(func(column, value string) string { return strings.TrimPrefix(value, "$") }),
+			},
+			expected: []Sale{{"north", 10}, {"south", 20}},
+		},
+		{
+			desc:      "skipping past the end of the file",
+			input:     "region,amount\n",
+			options:   []Option{SkipRows(3)},
+			expectErr: true,
+		},
+		{
+			desc:      "without options ragged rows are still an error",
+			input:     "region,amount\nnorth,10\nTotal\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			component := NewIsly(tc.options...)
+			assert.NoError(t, component.ReadFile(writeTestCSV(t, tc.input)))
+
+			var sales []Sale
+			err := component.UnmarshalCSV(&sales)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, sales)
+			}
+		})
+	}
+}
+
+func TestUnmarshalCSVFilteredRowNumbers(t *testing.T) {
+	type Sale struct {
+		Amount int `isly:"amount"`
+	}
+
+	component := NewIsly(WithRowFilter(func(record []string) bool { return record[0] != "skip" }))
+	assert.NoError(t, component.ReadFile(writeTestCSV(t, "amount\n1\nskip\nabc\n")))
+
+	// filtered rows still count, so the error points at the line in the file
+	var sales []Sale
+	err := component.UnmarshalCSV(&sales)
+	assert.ErrorContains(t, err, "row 3, column 'amount'")
+}
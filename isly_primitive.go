@@ -9,6 +9,8 @@ import (
 )
 
 var (
+	timeType = reflect.TypeOf(time.Time{})
+
 	// List of date formats
 	dateFormats = []string{
 		"2006-01-02",                // default
@@ -40,6 +42,15 @@ func islyParsePrimitiveData(fieldValue reflect.Value, value string, fieldType re
 		fieldValue.SetString(value)
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if fieldType == durationType {
+			durationVal, err := islyParseDuration(value, "")
+			if err != nil {
+				return err
+			}
+			fieldValue.SetInt(int64(durationVal))
+			return nil
+		}
+
 		intVal, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
 			return fmt.Errorf("failed to parse integer value '%s': %w", value, err)
@@ -76,7 +87,7 @@ func islyParsePrimitiveData(fieldValue reflect.Value, value string, fieldType re
 		}
 
 	case reflect.Struct:
-		if fieldType == reflect.TypeOf(time.Time{}) {
+		if fieldType == timeType {
 			if dateFormat != "" {
 				timeVal, err := time.Parse(dateFormat, value)
 				if err == nil {
@@ -363,6 +363,32 @@ func TestIslyParsePrimitiveData(t *testing.T) {
 			expectErr:  true,
 		},
 
+		// Duration
+		{
+			desc:      "duration - go format",
+			fieldType: reflect.TypeOf(time.Duration(0)),
+			value:     "1h30m",
+			expectErr: false,
+			validate: func(t *testing.T, value reflect.Value) {
+				assert.Equal(t, 90*time.Minute, value.Interface().(time.Duration))
+			},
+		},
+		{
+			desc:      "duration - ISO-8601",
+			fieldType: reflect.TypeOf(time.Duration(0)),
+			value:     "PT1H30M",
+			expectErr: false,
+			validate: func(t *testing.T, value reflect.Value) {
+				assert.Equal(t, 90*time.Minute, value.Interface().(time.Duration))
+			},
+		},
+		{
+			desc:      "duration - invalid",
+			fieldType: reflect.TypeOf(time.Duration(0)),
+			value:     "later",
+			expectErr: true,
+		},
+
 		// Unsupported types
 		{
 			desc:      "unsupported - complex",
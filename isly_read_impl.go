@@ -1,42 +1,106 @@
 package isly
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
-	"strings"
+	"time"
 )
 
+// ReadFile opens csvFile for the next UnmarshalCSV call. gzip, bzip2, zstd and zip files
+// are decompressed on the fly. A file opened by an earlier call that was never decoded is
+// closed first, so the component can be reused.
 func (i *newIslyComponent) ReadFile(csvFile string) error {
+	if err := i.Close(); err != nil {
+		return err
+	}
+
 	file, err := os.Open(csvFile)
 	if err != nil {
 		return err
 	}
+
+	sourceRead := islyCountFile(file)
+	source, sourceCloser, err := islyDecompress(sourceRead, csvFile, i.decoder.options.zipEntry)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("%s: %w", csvFile, err)
+	}
+
 	i.File = file
+	i.source = source
+	i.sourceCloser = sourceCloser
+	i.sourceRead = sourceRead
 	return nil
 }
 
-func (i *newIslyComponent) UnmarshalCSV(results interface{}) error {
-	// file must close!
-	defer i.File.Close()
+// Close closes the file opened by ReadFile and forgets the files given to ReadFiles or
+// ReadGlob. It is safe to call when no file is open and more than once.
+func (i *newIslyComponent) Close() error {
+	i.files = nil
 
 	if i.File == nil {
-		return fmt.Errorf("no file provided")
+		return nil
 	}
 
-	reader := csv.NewReader(i.File)
+	var err error
+	if i.sourceCloser != nil {
+		err = i.sourceCloser.Close()
+	}
+	if closeErr := i.File.Close(); err == nil {
+		err = closeErr
+	}
 
-	// Read header
-	header, err := reader.Read()
-	if err != nil {
-		return fmt.Errorf("failed to read CSV header: %w", err)
+	i.File = nil
+	i.source = nil
+	i.sourceCloser = nil
+	i.sourceRead = nil
+	return err
+}
+
+func (i *newIslyComponent) UnmarshalCSV(results interface{}) error {
+	return i.UnmarshalCSVContext(context.Background(), results)
+}
+
+// UnmarshalCSVContext works like UnmarshalCSV and stops with ctx.Err() as soon as ctx is
+// cancelled. Cancellation is checked between rows.
+func (i *newIslyComponent) UnmarshalCSVContext(ctx context.Context, results interface{}) error {
+	var sources []islySource
+	switch {
+	case i.File != nil:
+		source, sourceRead := i.source, i.sourceRead
+		sources = []islySource{{
+			name: i.File.Name(),
+			open: func() (io.Reader, io.Closer, *islyByteCounter, error) { return source, nil, sourceRead, nil },
+		}}
+	case len(i.files) > 0:
+		sources = islyFileSources(i.files, i.decoder.options.zipEntry)
+	default:
+		return ErrNoFile
 	}
 
-	// Create a map of header indices
-	headerMap := make(map[string]int)
-	for i, h := range header {
-		headerMap[h] = i
+	// the files are consumed by this call, close them whatever happens
+	defer i.Close()
+
+	return i.decoder.decode(ctx, sources, results)
+}
+
+// islySource is one CSV input of a decode. open is called right before it is read, so
+// only one file of a ReadFiles call is open at a time. It returns the CSV text, an
+// optional closer and the counter of the bytes read from the source.
+type islySource struct {
+	name string
+	open func() (io.Reader, io.Closer, *islyByteCounter, error)
+}
+
+// decode reads the header and data rows of every source into results, a pointer to a
+// struct or to a slice of structs. A struct is filled from the first data row.
+func (d *Decoder) decode(ctx context.Context, sources []islySource, results interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	resultsValue := reflect.ValueOf(results)
@@ -47,75 +111,199 @@ func (i *newIslyComponent) UnmarshalCSV(results interface{}) error {
 	// Dereference the pointer to get the actual value
 	resultsElem := resultsValue.Elem()
 
+	run := &islyDecodeRun{decoder: d, ctx: ctx, target: resultsElem}
+	switch resultsElem.Kind() {
+	case reflect.Slice:
+		if resultsElem.Type().Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("results must be a pointer to a struct or a slice of structs")
+		}
+		run.slice = reflect.MakeSlice(resultsElem.Type(), 0, 0)
+	case reflect.Struct:
+	default:
+		return fmt.Errorf("results must be a pointer to a struct or a slice of structs")
+	}
+
+	for _, source := range sources {
+		err := run.readSource(source)
+		if err != nil && len(sources) > 1 {
+			return fmt.Errorf("%s: %w", source.name, err)
+		}
+		if err != nil {
+			return err
+		}
+		if run.done {
+			break
+		}
+	}
+
 	if resultsElem.Kind() == reflect.Slice {
-		sliceElemType := resultsElem.Type().Elem()
+		// Set the result slice
+		resultsElem.Set(run.slice)
+	} else if !run.done {
+		return fmt.Errorf("failed to read CSV record: %w", io.EOF)
+	}
+
+	return nil
+}
+
+// islyDecodeRun holds the state of one decode across its sources.
+type islyDecodeRun struct {
+	decoder *Decoder
+	ctx     context.Context
+	target  reflect.Value
+	slice   reflect.Value
+
+	// header of the first source, the others must match it
+	header []string
+	// rows decoded and bytes read from earlier sources, for progress
+	decoded     int
+	bytesBefore int64
+	// set once a struct target is filled
+	done bool
+}
+
+func (run *islyDecodeRun) readSource(source islySource) error {
+	d := run.decoder
+
+	input, closer, sourceRead, err := source.open()
+	if err != nil {
+		return err
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	text, err := islyDecodeText(input, d.options.encoding)
+	if err != nil {
+		return err
+	}
+
+	reader := d.options.islyNewCSVReader(csv.NewReader(text))
+
+	// Read header
+	header, err := d.options.readHeader(reader)
+	if err != nil {
+		return err
+	}
+
+	// Create a map of header indices
+	columns, err := islyBuildHeader(header, d.options.duplicateHeaders)
+	if err != nil {
+		return err
+	}
+	header = columns.names
 
-		// Read all CSV records
-		records, err := reader.ReadAll()
+	if run.header == nil {
+		run.header = header
+	} else if err := islyCompareHeaders(run.header, header); err != nil {
+		return err
+	}
+
+	rows := newIslyRowReader(run.ctx, reader, d.options)
+	defer func() {
+		run.bytesBefore += sourceRead.n
+	}()
+
+	// Process each record
+	for {
+		record, err := rows.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil && err == run.ctx.Err() {
+			return fmt.Errorf("decoding stopped after %d rows: %w", run.decoded, err)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to read CSV records: %w", err)
 		}
 
-		slice := reflect.MakeSlice(resultsElem.Type(), len(records), len(records))
+		rowCtx := RowContext{Row: record.row, Line: record.line, Source: source.name, Header: header, Record: record.cells}
 
-		// Process each record
-		for index, record := range records {
-			item := reflect.New(sliceElemType).Elem()
-
-			if err := i.processStructFromRecord(item, record, headerMap); err != nil {
-				return fmt.Errorf("error processing row %d: %w", index+1, err)
+		if run.slice.IsValid() {
+			item := reflect.New(run.slice.Type().Elem()).Elem()
+			if err := d.decodeRow(item, rowCtx, columns); err != nil {
+				return err
 			}
 
 			// Add the item to the slice
-			slice.Index(index).Set(item)
+			run.slice = reflect.Append(run.slice, item)
+		} else {
+			// Fill the struct with the first data row
+			if err := d.decodeRow(run.target, rowCtx, columns); err != nil {
+				return err
+			}
+			run.done = true
 		}
 
-		// Set the result slice
-		resultsElem.Set(slice)
-	} else if resultsElem.Kind() == reflect.Struct {
-		// Read the first data row
-		record, err := reader.Read()
-		if err != nil {
-			return fmt.Errorf("failed to read CSV record: %w", err)
+		run.decoded++
+		if d.options.progress != nil {
+			d.options.progress(run.decoded, run.bytesBefore+sourceRead.n)
 		}
 
-		// Fill the struct with data
-		if err := i.processStructFromRecord(resultsElem, record, headerMap); err != nil {
-			return fmt.Errorf("error processing row: %w", err)
+		if run.done {
+			return nil
 		}
-	} else {
-		return fmt.Errorf("results must be a pointer to a struct or a slice of structs")
+	}
+}
+
+// decodeRow fills item from one data row and runs the row hooks on it.
+func (d *Decoder) decodeRow(item reflect.Value, ctx RowContext, columns *islyHeader) error {
+	cells := d.options.transformRecord(ctx.Header, ctx.Record)
+	if err := d.processStructFromRecord(item, cells, columns.index, columns.duplicates); err != nil {
+		return islyRowError(err, ctx.Row)
+	}
+
+	if err := islySetMetadata(item, d.plan(item.Type()), ctx, columns); err != nil {
+		return islyRowError(err, ctx.Row)
+	}
+
+	// Let the struct check itself or fill derived fields
+	if err := islyRunRowHooks(item, ctx); err != nil {
+		return islyRowError(err, ctx.Row)
 	}
 
 	return nil
 }
 
 func (i *newIslyComponent) processStructFromRecord(structValue reflect.Value, record []string, headerMap map[string]int) error {
-	structType := structValue.Type()
-
-	for j := 0; j < structValue.NumField(); j++ {
-		field := structValue.Field(j)
-		fieldType := structType.Field(j)
+	return i.decoder.processStructFromRecord(structValue, record, headerMap, nil)
+}
 
-		// Skip unexported fields
-		if !field.CanSet() {
+// processStructFromRecord fills structValue from record. duplicates lists every column
+// of a header name that appears more than once, for fields with the all flag.
+func (d *Decoder) processStructFromRecord(structValue reflect.Value, record []string, headerMap map[string]int, duplicates map[string][]int) error {
+	for _, planned := range d.plan(structValue.Type()).fields {
+		// metadata fields are not bound to a column
+		if planned.meta != "" {
 			continue
 		}
 
-		// Get field tag
-		tag := fieldType.Tag.Get("isly")
-		if tag == "" {
+		field := structValue.Field(planned.index)
+
+		// Skip values that can't be set
+		if !field.CanSet() {
 			continue
 		}
 
-		// Parse tag
-		tagParts := strings.Split(tag, ",")
-		if len(tagParts) == 0 {
+		csvFieldName := planned.tag.name
+
+		// collect every column with this name into a slice
+		if planned.tag.hasOption("all") {
+			indices, exists := duplicates[csvFieldName]
+			if !exists {
+				fieldIndex, found := headerMap[csvFieldName]
+				if !found {
+					continue
+				}
+				indices = []int{fieldIndex}
+			}
+
+			if value, err := islyParseColumns(field, record, indices, planned.tag); err != nil {
+				return &FieldError{Column: csvFieldName, Value: value, Err: err}
+			}
 			continue
 		}
 
-		csvFieldName := strings.TrimSpace(tagParts[0])
-
 		fieldIndex, exists := headerMap[csvFieldName]
 		if !exists {
 			// Field not found in CSV
@@ -131,47 +319,95 @@ func (i *newIslyComponent) processStructFromRecord(structValue reflect.Value, re
 		value := record[fieldIndex]
 
 		// Handle different field types based on tag
-		var err error
-		if len(tagParts) > 1 {
-			tagType := strings.TrimSpace(tagParts[1])
-
-			switch tagType {
-			case "list":
-				listValue := islyParseList(value, field.Type())
-				if listValue.IsValid() {
-					field.Set(listValue)
-				}
+		if err := islyParseField(field, value, planned.tag); err != nil {
+			return &FieldError{Column: csvFieldName, Value: value, Err: err}
+		}
 
-			case "json":
-				jsonValue := islyParseJSON(value, field.Type())
-				if jsonValue.IsValid() {
-					field.Set(jsonValue)
-				}
+		// Check validation rules on the converted value
+		if err := islyValidateField(field, value, planned.tag); err != nil {
+			return &FieldError{Column: csvFieldName, Value: value, Err: err}
+		}
+	}
 
-			case "hex":
-				hexValue := islyParseHex(value)
-				if hexValue != nil {
-					field.SetBytes(hexValue)
-				}
+	return nil
+}
 
-			case "binary":
-				binaryValue := islyParseBinary(value)
-				if binaryValue != nil {
-					field.SetBytes(binaryValue)
-				}
+// islyParseField converts a single CSV cell into field according to its parsed tag.
+func islyParseField(field reflect.Value, value string, parsedTag islyTag) error {
+	if parsedTag.hasOption("enum") {
+		return islyParseEnum(field, value, parsedTag)
+	}
 
-			default:
-				err = islyParsePrimitiveData(field, value, field.Type(), tagType)
-			}
-		} else {
-			// No special type, parse as primitive
-			err = islyParsePrimitiveData(field, value, field.Type(), "")
+	var err error
+
+	switch parsedTag.kind {
+	case "list":
+		var listValue reflect.Value
+		listValue, err = islyParseList(value, field.Type(), islyListOptionsFromTag(parsedTag))
+		if err == nil {
+			field.Set(listValue)
 		}
 
-		if err != nil {
-			return fmt.Errorf("error parsing field '%s': %w", csvFieldName, err)
+	case "kv":
+		var kvValue reflect.Value
+		kvValue, err = islyParseKV(value, field.Type(), islyListOptionsFromTag(parsedTag), parsedTag.option("kvsep"))
+		if err == nil {
+			field.Set(kvValue)
+		}
+
+	case "json":
+		var jsonValue reflect.Value
+		jsonValue, err = islyParseJSON(value, field.Type(), islyJSONOptionsFromTag(parsedTag))
+		if err == nil {
+			field.Set(jsonValue)
+		}
+
+	case "hex":
+		var hexValue []byte
+		hexValue, err = islyParseHex(value)
+		if err == nil {
+			err = islySetBytes(field, hexValue)
+		}
+
+	case "binary":
+		var binaryValue []byte
+		binaryValue, err = islyParseBinary(value, islyBinaryNumeric(field.Type(), parsedTag))
+		if err == nil {
+			err = islySetBytes(field, binaryValue)
+		}
+
+	case "base64", "base64url", "rawbase64", "base32", "base58":
+		var decoded []byte
+		decoded, err = islyParseBaseEncoding(value, parsedTag.kind)
+		if err == nil {
+			err = islySetBytes(field, decoded)
+		}
+
+	case "excel":
+		err = islyParseExcelField(field, value, parsedTag.option("epoch"))
+
+	case "decimal":
+		err = islyParseDecimalField(field, value, parsedTag)
+
+	default:
+		switch {
+		case field.Type() == durationType:
+			var durationVal time.Duration
+			durationVal, err = islyParseDuration(value, parsedTag.option("unit"))
+			if err == nil {
+				field.SetInt(int64(durationVal))
+			}
+
+		case parsedTag.hasOption("scale"):
+			err = islyParseDecimalField(field, value, parsedTag)
+
+		case islyIsBigNumber(field.Type()):
+			err = islyParseBigNumber(field, value, parsedTag)
+
+		default:
+			err = islyParsePrimitiveData(field, value, field.Type(), parsedTag.kind)
 		}
 	}
 
-	return nil
+	return err
 }
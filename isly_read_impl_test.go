@@ -0,0 +1,215 @@
+package isly
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestFile writes content to name in a temporary directory and returns its path.
+func writeTestFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	writeFileAt(t, path, content)
+	return path
+}
+
+func writeFileAt(t *testing.T, path string, content []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+}
+
+// writeTestCSV writes content to a CSV file in a temporary directory and returns its path.
+func writeTestCSV(t *testing.T, content string) string {
+	t.Helper()
+	return writeTestFile(t, "test.csv", []byte(content))
+}
+
+func TestProcessStructFromRecord(t *testing.T) {
+	type Row struct {
+		Name   string            `isly:"name"`
+		Scores []int             `isly:"scores, list"`
+		Tags   []string          `isly:"tags, list, sep=|"`
+		Meta   map[string]string `isly:"meta, json"`
+		Color  [3]byte           `isly:"color, hex"`
+		Flags  uint16            `isly:"flags, binary"`
+	}
+
+	headerMap := map[string]int{"name": 0, "scores": 1, "tags": 2, "meta": 3, "color": 4, "flags": 5}
+
+	testCases := []struct {
+		desc      string
+		record    []string
+		expected  Row
+		expectErr bool
+	}{
+		{
+			desc:   "valid record",
+			record: []string{"John", "[1, 2, 3]", "a|b", "{'url': 'http://x'}"},
+			expected: Row{
+				Name:   "John",
+				Scores: []int{1, 2, 3},
+				Tags:   []string{"a", "b"},
+				Meta:   map[string]string{"url": "http://x"},
+			},
+		},
+		{
+			desc:      "invalid list element is reported",
+			record:    []string{"John", "[1, two, 3]", "a|b", "{}"},
+			expectErr: true,
+		},
+		{
+			desc:   "hex and binary into fixed size fields",
+			record: []string{"Jane", "", "", "", "0XFF:88:00", "0b1_0000_0001"},
+			expected: Row{
+				Name:   "Jane",
+				Scores: []int{},
+				Tags:   []string{},
+				Color:  [3]byte{0xFF, 0x88, 0x00},
+				Flags:  0x101,
+			},
+		},
+		{
+			desc:      "invalid hex is reported",
+			record:    []string{"Jane", "", "", "", "0xZZ"},
+			expectErr: true,
+		},
+		{
+			desc:      "binary overflow is reported",
+			record:    []string{"Jane", "", "", "", "", "0b1_0000_0000_0000_0000"},
+			expectErr: true,
+		},
+		{
+			desc:      "invalid json is reported",
+			record:    []string{"John", "[1]", "a", "{'url': 'http://x'"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var row Row
+			err := NewIsly().processStructFromRecord(reflect.ValueOf(&row).Elem(), tc.record, headerMap)
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+				assert.Equal(t, tc.expected, row)
+			}
+		})
+	}
+}
+
+func TestProcessStructFromRecordValidation(t *testing.T) {
+	type Row struct {
+		Name  string `isly:"name, notempty"`
+		Age   int    `isly:"age, min=0, max=150"`
+		Email string `isly:"email, email"`
+	}
+
+	headerMap := map[string]int{"name": 0, "age": 1, "email": 2}
+
+	var row Row
+	err := NewIsly().processStructFromRecord(reflect.ValueOf(&row).Elem(), []string{"John", "200", "john@example.com"}, headerMap)
+
+	var fieldErr *FieldError
+	if assert.True(t, errors.As(err, &fieldErr), "expected a *FieldError, got: %v", err) {
+		assert.Equal(t, "age", fieldErr.Column)
+		assert.Equal(t, "200", fieldErr.Value)
+	}
+	assert.ErrorIs(t, err, ErrValidation)
+
+	// the row number is added by the caller
+	err = islyRowError(err, 3)
+	assert.EqualError(t, err, "row 3, column 'age': validation failed: value '200' is greater than max 150")
+
+	// conversion errors are not validation errors
+	err = NewIsly().processStructFromRecord(reflect.ValueOf(&row).Elem(), []string{"John", "abc", ""}, headerMap)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrValidation))
+}
+
+func TestComponentLifecycle(t *testing.T) {
+	type Item struct {
+		ID int `isly:"id"`
+	}
+
+	t.Run("unmarshal without ReadFile", func(t *testing.T) {
+		component := NewIsly()
+
+		var items []Item
+		assert.NotPanics(t, func() {
+			assert.ErrorIs(t, component.UnmarshalCSV(&items), ErrNoFile)
+		})
+	})
+
+	t.Run("unmarshal after a failed ReadFile", func(t *testing.T) {
+		component := NewIsly()
+		assert.Error(t, component.ReadFile(filepath.Join(t.TempDir(), "missing.csv")))
+
+		var items []Item
+		assert.ErrorIs(t, component.UnmarshalCSV(&items), ErrNoFile)
+	})
+
+	t.Run("close without ReadFile and close twice", func(t *testing.T) {
+		component := NewIsly()
+		assert.NoError(t, component.Close())
+
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, "id\n1\n")))
+		assert.NoError(t, component.Close())
+		assert.NoError(t, component.Close())
+
+		var items []Item
+		assert.ErrorIs(t, component.UnmarshalCSV(&items), ErrNoFile)
+	})
+
+	t.Run("reuse across files", func(t *testing.T) {
+		component := NewIsly()
+
+		var first, second []Item
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, "id\n1\n2\n")))
+		assert.NoError(t, component.UnmarshalCSV(&first))
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, "id\n3\n")))
+		assert.NoError(t, component.UnmarshalCSV(&second))
+
+		assert.Equal(t, []Item{{1}, {2}}, first)
+		assert.Equal(t, []Item{{3}}, second)
+
+		// the file is consumed by UnmarshalCSV
+		assert.ErrorIs(t, component.UnmarshalCSV(&second), ErrNoFile)
+	})
+
+	t.Run("file is closed after a failed decode", func(t *testing.T) {
+		component := NewIsly()
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, "id\nabc\n")))
+
+		file := component.(*newIslyComponent).File
+
+		var items []Item
+		assert.Error(t, component.UnmarshalCSV(&items))
+		assert.ErrorIs(t, file.Close(), os.ErrClosed)
+	})
+
+	t.Run("ReadFile closes a file that was never decoded", func(t *testing.T) {
+		component := NewIsly()
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, "id\n1\n")))
+
+		file := component.(*newIslyComponent).File
+
+		assert.NoError(t, component.ReadFile(writeTestCSV(t, "id\n2\n")))
+		assert.ErrorIs(t, file.Close(), os.ErrClosed)
+
+		var items []Item
+		assert.NoError(t, component.UnmarshalCSV(&items))
+		assert.Equal(t, []Item{{2}}, items)
+	})
+}
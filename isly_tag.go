@@ -0,0 +1,76 @@
+package isly
+
+import (
+	"strings"
+)
+
+// islyTag is the parsed form of an `isly:"..."` struct tag.
+//
+//	isly:"name"                 -> column only
+//	isly:"name, list"           -> column + kind
+//	isly:"timeout, unit=ms"     -> column + option
+type islyTag struct {
+	name    string
+	kind    string
+	options map[string]string
+}
+
+// Bare tag options that are never a kind or date layout
+var tagFlags = map[string]bool{
+	"strict":     true,
+	"usenumber":  true,
+	"numeric":    true,
+	"ignorecase": true,
+	"notempty":   true,
+	"email":      true,
+	"url":        true,
+	"all":        true,
+}
+
+func islyParseTag(tag string) islyTag {
+	parts := strings.Split(tag, ",")
+
+	parsed := islyTag{
+		name:    strings.TrimSpace(parts[0]),
+		options: make(map[string]string),
+	}
+
+	for i, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		// a regex may contain commas, it takes the rest of the tag
+		if strings.HasPrefix(part, "regex=") {
+			parsed.options["regex"] = strings.TrimPrefix(strings.TrimSpace(strings.Join(parts[i+1:], ",")), "regex=")
+			break
+		}
+
+		// key=value option
+		if key, value, ok := strings.Cut(part, "="); ok {
+			parsed.options[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			continue
+		}
+
+		// the first bare part that isn't a flag is the kind (list, json, hex, binary or a date layout)
+		if parsed.kind == "" && !tagFlags[part] {
+			parsed.kind = part
+			continue
+		}
+
+		// any other bare part is a flag
+		parsed.options[part] = ""
+	}
+
+	return parsed
+}
+
+func (t islyTag) option(key string) string {
+	return t.options[key]
+}
+
+func (t islyTag) hasOption(key string) bool {
+	_, ok := t.options[key]
+	return ok
+}
@@ -0,0 +1,62 @@
+package isly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslyParseTag(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    string
+		expected islyTag
+	}{
+		{
+			desc:     "name only",
+			input:    "id",
+			expected: islyTag{name: "id", options: map[string]string{}},
+		},
+		{
+			desc:     "name and kind with spaces",
+			input:    "scores, list",
+			expected: islyTag{name: "scores", kind: "list", options: map[string]string{}},
+		},
+		{
+			desc:     "date layout",
+			input:    "created_at, 2006-01-02 15:04:05",
+			expected: islyTag{name: "created_at", kind: "2006-01-02 15:04:05", options: map[string]string{}},
+		},
+		{
+			desc:     "option without kind",
+			input:    "timeout,unit=ms",
+			expected: islyTag{name: "timeout", options: map[string]string{"unit": "ms"}},
+		},
+		{
+			desc:     "kind, option and flag",
+			input:    "tags, list, sep=|, strict",
+			expected: islyTag{name: "tags", kind: "list", options: map[string]string{"sep": "|", "strict": ""}},
+		},
+		{
+			desc:     "flag without kind",
+			input:    "status, enum=a|b, ignorecase",
+			expected: islyTag{name: "status", options: map[string]string{"enum": "a|b", "ignorecase": ""}},
+		},
+		{
+			desc:     "regex takes the rest of the tag",
+			input:    "code, notempty, regex=^[A-Z]{2,4}$",
+			expected: islyTag{name: "code", options: map[string]string{"notempty": "", "regex": "^[A-Z]{2,4}$"}},
+		},
+		{
+			desc:     "empty parts are ignored",
+			input:    "name,,",
+			expected: islyTag{name: "name", options: map[string]string{}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, islyParseTag(tc.input))
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package isly
+
+import (
+	"cmp"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Compiled `regex=` patterns, shared by every decode
+var validateRegexCache sync.Map
+
+// islyValidateField applies the validation rules of tag to a converted field. raw is the
+// CSV cell the field was parsed from; regex= and oneof= match against it. Empty cells
+// only fail notempty, every other rule is skipped for them.
+func islyValidateField(field reflect.Value, raw string, tag islyTag) error {
+	raw = strings.TrimSpace(raw)
+
+	if tag.hasOption("notempty") && (raw == "" || islyHasZeroLength(field)) {
+		return fmt.Errorf("%w: value must not be empty", ErrValidation)
+	}
+
+	if raw == "" {
+		return nil
+	}
+
+	// pointers are validated through the value they point to
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+
+	if limit, ok := tag.options["min"]; ok {
+		result, err := islyCompareLimit(field, limit, tag)
+		if err != nil {
+			return fmt.Errorf("min: %w", err)
+		}
+		if result < 0 {
+			return fmt.Errorf("%w: value '%s' is less than min %s", ErrValidation, raw, limit)
+		}
+	}
+
+	if limit, ok := tag.options["max"]; ok {
+		result, err := islyCompareLimit(field, limit, tag)
+		if err != nil {
+			return fmt.Errorf("max: %w", err)
+		}
+		if result > 0 {
+			return fmt.Errorf("%w: value '%s' is greater than max %s", ErrValidation, raw, limit)
+		}
+	}
+
+	if rawLen, ok := tag.options["len"]; ok {
+		expected, err := strconv.Atoi(rawLen)
+		if err != nil {
+			return fmt.Errorf("invalid len '%s': %w", rawLen, err)
+		}
+		length, ok := islyLength(field)
+		if !ok {
+			return fmt.Errorf("len is not supported for %v", field.Type())
+		}
+		if length != expected {
+			return fmt.Errorf("%w: length %d is not %d", ErrValidation, length, expected)
+		}
+	}
+
+	if choices, ok := tag.options["oneof"]; ok {
+		found := false
+		for _, choice := range strings.Split(choices, "|") {
+			if strings.TrimSpace(choice) == raw {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: value '%s' is not one of %s", ErrValidation, raw, choices)
+		}
+	}
+
+	if pattern, ok := tag.options["regex"]; ok {
+		re, err := islyCompileRegex(pattern)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(raw) {
+			return fmt.Errorf("%w: value '%s' does not match %s", ErrValidation, raw, pattern)
+		}
+	}
+
+	if tag.hasOption("email") {
+		addr, err := mail.ParseAddress(raw)
+		if err != nil || addr.Address != raw {
+			return fmt.Errorf("%w: value '%s' is not an email address", ErrValidation, raw)
+		}
+	}
+
+	if tag.hasOption("url") {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%w: value '%s' is not a url", ErrValidation, raw)
+		}
+	}
+
+	return nil
+}
+
+func islyCompileRegex(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := validateRegexCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex '%s': %w", pattern, err)
+	}
+	validateRegexCache.Store(pattern, re)
+
+	return re, nil
+}
+
+// islyLength returns the rune count of strings and the length of slices, arrays and maps.
+func islyLength(field reflect.Value) (int, bool) {
+	switch field.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(field.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return field.Len(), true
+	}
+	return 0, false
+}
+
+func islyHasZeroLength(field reflect.Value) bool {
+	if field.Kind() == reflect.Ptr {
+		return field.IsNil()
+	}
+	length, ok := islyLength(field)
+	return ok && length == 0
+}
+
+// islyCompareLimit compares field with a min=/max= limit and returns -1, 0 or +1.
+// Numbers, durations, dates and math/big values compare by value, strings, slices,
+// arrays and maps by length.
+func islyCompareLimit(field reflect.Value, limit string, tag islyTag) (int, error) {
+	fieldType := field.Type()
+
+	switch {
+	case fieldType == durationType:
+		d, err := islyParseDuration(limit, tag.option("unit"))
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(time.Duration(field.Int()), d), nil
+
+	case fieldType == timeType:
+		// the limit is written in the same layout as the column
+		limitValue := reflect.New(timeType).Elem()
+		if err := islyParseField(limitValue, limit, tag); err != nil {
+			return 0, err
+		}
+		return field.Interface().(time.Time).Compare(limitValue.Interface().(time.Time)), nil
+
+	case tag.kind == "decimal" || tag.hasOption("scale"):
+		// the limit is a decimal like the column, scaled the same way
+		limitValue := reflect.New(fieldType).Elem()
+		if err := islyParseField(limitValue, limit, tag); err != nil {
+			return 0, fmt.Errorf("invalid limit '%s': %w", limit, err)
+		}
+		current, err := islyRatValue(field)
+		if err != nil {
+			return 0, err
+		}
+		bound, err := islyRatValue(limitValue)
+		if err != nil {
+			return 0, err
+		}
+		return current.Cmp(bound), nil
+
+	case islyIsBigNumber(fieldType):
+		limitValue, ok := new(big.Rat).SetString(limit)
+		if !ok {
+			return 0, fmt.Errorf("invalid limit '%s'", limit)
+		}
+		current, err := islyRatValue(field)
+		if err != nil {
+			return 0, err
+		}
+		return current.Cmp(limitValue), nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid limit '%s': %w", limit, err)
+		}
+		return cmp.Compare(field.Int(), n), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid limit '%s': %w", limit, err)
+		}
+		return cmp.Compare(field.Uint(), n), nil
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(limit, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid limit '%s': %w", limit, err)
+		}
+		return cmp.Compare(field.Float(), n), nil
+	}
+
+	if length, ok := islyLength(field); ok {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return 0, fmt.Errorf("invalid limit '%s': %w", limit, err)
+		}
+		return cmp.Compare(length, n), nil
+	}
+
+	return 0, fmt.Errorf("not supported for %v", fieldType)
+}
+
+// islyRatValue returns the exact value of a decimal string, integer or math/big field.
+func islyRatValue(field reflect.Value) (*big.Rat, error) {
+	switch field.Kind() {
+	case reflect.String:
+		n, ok := new(big.Rat).SetString(field.String())
+		if !ok {
+			return nil, fmt.Errorf("invalid decimal '%s'", field.String())
+		}
+		return n, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(field.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(field.Uint())), nil
+	}
+
+	switch n := field.Addr().Interface().(type) {
+	case *big.Int:
+		return new(big.Rat).SetInt(n), nil
+	case *big.Rat:
+		return n, nil
+	case *big.Float:
+		// Rat has no value for ±Inf
+		if n.IsInf() {
+			return nil, fmt.Errorf("%w: infinite value '%s' is out of range", ErrValidation, n.String())
+		}
+		r, _ := n.Rat(nil)
+		return r, nil
+	}
+
+	return nil, fmt.Errorf("not supported for %v", field.Type())
+}
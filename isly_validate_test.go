@@ -0,0 +1,147 @@
+package isly
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslyValidateField(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		value     interface{}
+		raw       string
+		tag       string
+		expectErr bool
+	}{
+		// min / max
+		{desc: "int within range", value: 5, raw: "5", tag: "n, min=1, max=10"},
+		{desc: "int below min", value: 0, raw: "0", tag: "n, min=1", expectErr: true},
+		{desc: "int above max", value: 11, raw: "11", tag: "n, max=10", expectErr: true},
+		{desc: "uint above max", value: uint8(200), raw: "200", tag: "n, max=100", expectErr: true},
+		{desc: "float within range", value: 0.5, raw: "0.5", tag: "n, min=0, max=1"},
+		{desc: "float below min", value: -0.1, raw: "-0.1", tag: "n, min=0", expectErr: true},
+		{desc: "duration above max", value: 2 * time.Hour, raw: "2h", tag: "n, max=90m", expectErr: true},
+		{desc: "duration with unit", value: 30 * time.Second, raw: "30", tag: "n, unit=s, max=60", expectErr: false},
+		{desc: "date before min", value: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), raw: "2019-01-01", tag: "d, min=2020-01-01", expectErr: true},
+		{desc: "date after min", value: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), raw: "2021-01-01", tag: "d, min=2020-01-01"},
+		{desc: "date with the column layout", value: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), raw: "01.03.2021", tag: "d, 02.01.2006, min=01.01.2020"},
+		{desc: "date before min in the column layout", value: time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), raw: "01.03.2019", tag: "d, 02.01.2006, min=01.01.2020", expectErr: true},
+		{desc: "excel date limit", value: time.Date(2023, 7, 16, 0, 0, 0, 0, time.UTC), raw: "45123", tag: "d, excel, max=45000", expectErr: true},
+		{desc: "big.Float infinity", value: *new(big.Float).SetInf(false), raw: "Inf", tag: "n, max=10", expectErr: true},
+		{desc: "*big.Float negative infinity", value: new(big.Float).SetInf(true), raw: "-Inf", tag: "n, min=0", expectErr: true},
+		{desc: "big.Float within range", value: *big.NewFloat(2.5), raw: "2.5", tag: "n, min=1, max=10"},
+		{desc: "big.Int above max", value: *big.NewInt(1001), raw: "1001", tag: "n, max=1000", expectErr: true},
+		{desc: "decimal within range", value: "99.99", raw: "99.99", tag: "a, decimal, min=0, max=100"},
+		{desc: "decimal above max", value: "5000000.00", raw: "5000000.00", tag: "a, decimal, max=100", expectErr: true},
+		{desc: "decimal below min", value: "-1", raw: "-1", tag: "a, decimal, min=0", expectErr: true},
+		{desc: "decimal limit with fraction", value: "0.5", raw: "0.5", tag: "a, decimal, min=0.75", expectErr: true},
+		{desc: "decimal big.Rat above max", value: *big.NewRat(201, 2), raw: "100.50", tag: "a, decimal, max=100", expectErr: true},
+		{desc: "scaled within range", value: int64(1234), raw: "12.34", tag: "a, scale=2, max=100"},
+		{desc: "scaled above max", value: int64(10001), raw: "100.01", tag: "a, scale=2, max=100", expectErr: true},
+		{desc: "scaled below min", value: int64(-1), raw: "-0.01", tag: "a, scale=2, min=0", expectErr: true},
+		{desc: "scaled limit with fraction", value: uint32(1234), raw: "12.34", tag: "a, scale=2, min=12.35", expectErr: true},
+		{desc: "scaled big.Int within range", value: *big.NewInt(1234), raw: "12.34", tag: "a, decimal, scale=2, max=12.34"},
+		{desc: "scaled limit with too many digits", value: int64(1234), raw: "12.34", tag: "a, scale=2, max=12.345", expectErr: true},
+		{desc: "string length min", value: "ab", raw: "ab", tag: "s, min=3", expectErr: true},
+		{desc: "string length max counts runes", value: "héllo", raw: "héllo", tag: "s, max=5"},
+		{desc: "slice length max", value: []int{1, 2, 3}, raw: "[1,2,3]", tag: "s, list, max=2", expectErr: true},
+		{desc: "pointer value", value: func() *int { n := 20; return &n }(), raw: "20", tag: "n, max=10", expectErr: true},
+		{desc: "invalid limit", value: 5, raw: "5", tag: "n, min=one", expectErr: true},
+		{desc: "unsupported type", value: true, raw: "true", tag: "b, min=1", expectErr: true},
+
+		// len
+		{desc: "exact length", value: "ABC", raw: "ABC", tag: "s, len=3"},
+		{desc: "wrong length", value: "ABCD", raw: "ABCD", tag: "s, len=3", expectErr: true},
+		{desc: "list length", value: []string{"a", "b"}, raw: "a,b", tag: "s, list, len=2"},
+
+		// oneof
+		{desc: "oneof match", value: "EUR", raw: "EUR", tag: "c, oneof=USD|EUR"},
+		{desc: "oneof mismatch", value: "JPY", raw: "JPY", tag: "c, oneof=USD|EUR", expectErr: true},
+
+		// regex
+		{desc: "regex match", value: "AB-123", raw: "AB-123", tag: `s, regex=^[A-Z]{2}-\d+$`},
+		{desc: "regex with comma", value: "ABC", raw: "ABC", tag: `s, regex=^[A-Z]{2,4}$`},
+		{desc: "regex mismatch", value: "ab-123", raw: "ab-123", tag: `s, regex=^[A-Z]{2}-\d+$`, expectErr: true},
+		{desc: "invalid regex", value: "a", raw: "a", tag: `s, regex=([`, expectErr: true},
+
+		// notempty
+		{desc: "notempty with value", value: "x", raw: "x", tag: "s, notempty"},
+		{desc: "notempty empty cell", value: "", raw: "  ", tag: "s, notempty", expectErr: true},
+		{desc: "notempty empty list", value: []string{}, raw: "[]", tag: "s, list, notempty", expectErr: true},
+		{desc: "notempty zero number is allowed", value: 0, raw: "0", tag: "n, notempty"},
+
+		// email
+		{desc: "valid email", value: "john@example.com", raw: "john@example.com", tag: "e, email"},
+		{desc: "invalid email", value: "john@", raw: "john@", tag: "e, email", expectErr: true},
+		{desc: "email with display name", value: "John <john@example.com>", raw: "John <john@example.com>", tag: "e, email", expectErr: true},
+
+		// url
+		{desc: "valid url", value: "https://example.com/a?b=c", raw: "https://example.com/a?b=c", tag: "u, url"},
+		{desc: "url without scheme", value: "example.com", raw: "example.com", tag: "u, url", expectErr: true},
+
+		// empty cells skip every rule but notempty
+		{desc: "empty cell skips min", value: 0, raw: "", tag: "n, min=1"},
+		{desc: "empty cell skips email", value: "", raw: "", tag: "e, email"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			field := reflect.New(reflect.TypeOf(tc.value)).Elem()
+			field.Set(reflect.ValueOf(tc.value))
+
+			err := islyValidateField(field, tc.raw, islyParseTag(tc.tag))
+
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error but got nil")
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestIslyValidateFieldInfinity(t *testing.T) {
+	type Row struct {
+		Amount big.Float `isly:"amount, max=1000"`
+	}
+
+	var row Row
+	err := NewIsly().processStructFromRecord(reflect.ValueOf(&row).Elem(), []string{"Inf"}, map[string]int{"amount": 0})
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestIslyValidateDecimalLimits(t *testing.T) {
+	type Payment struct {
+		Amount string `isly:"amount, decimal, max=100"`
+		Cents  int64  `isly:"cents, scale=2, min=0, max=100"`
+	}
+
+	testCases := []struct {
+		desc      string
+		input     string
+		expectErr bool
+	}{
+		{desc: "within range", input: "amount,cents\n99.99,12.34\n"},
+		{desc: "decimal above max", input: "amount,cents\n5000000.00,1\n", expectErr: true},
+		{desc: "scaled above max", input: "amount,cents\n1,100.01\n", expectErr: true},
+		{desc: "scaled below min", input: "amount,cents\n1,-0.01\n", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var payments []Payment
+			err := NewDecoder().NewReader(strings.NewReader(tc.input)).UnmarshalCSV(&payments)
+
+			if tc.expectErr {
+				assert.ErrorIs(t, err, ErrValidation)
+			} else {
+				assert.NoError(t, err, "expected no error but got: %v", err)
+			}
+		})
+	}
+}